@@ -11,7 +11,8 @@ type APIConfig struct {
 	Host           string
 	Port           int
 	FileStore      *filestore.FileStore
-	DestinationCRS int
+	DestinationCRS string // EPSG code, WKT or PROJ string
+	ExportPrefix   string // file store prefix exported files can be written under
 }
 
 // Address tells the application where to run the api out of
@@ -25,7 +26,11 @@ func Init() *APIConfig {
 	config.Host = "" // 0.0.0.0
 	config.Port = 5600
 	config.FileStore = FileStoreInit(os.Getenv("STORE_TYPE"))
-	config.DestinationCRS = 4326
+	config.DestinationCRS = "4326"
+	config.ExportPrefix = os.Getenv("EXPORT_PREFIX")
+	if config.ExportPrefix == "" {
+		config.ExportPrefix = "exports/"
+	}
 	return config
 }
 
@@ -28,6 +28,44 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/forcingdata": {
+            "get": {
+                "description": "forcing data from a RAS model given an s3 key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MCAT"
+                ],
+                "summary": "Extract forcing data from flow files",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "/models/ras/CHURCH HOUSE GULLY/CHURCH HOUSE GULLY.prj",
+                        "name": "definition_file",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SimpleResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/geospatialdata": {
             "get": {
                 "description": "Extract geospatial data from a RAS model given an s3 key",
@@ -48,6 +86,198 @@ var doc = `{
                         "name": "definition_file",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "destination CRS as an EPSG code, WKT or PROJ string, or native for the model projection, 4326 by default",
+                        "name": "crs",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) with WKB geometries, geojson for one FeatureCollection per layer, ndjson to stream one GeoJSON Feature per line, or a file format: gpkg, shapefile (zip), flatgeobuf (zip) or kml",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "new file store key under the export prefix to write file formats to instead of returning them, POST only",
+                        "name": "output_file",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include 2D area cell centers",
+                        "name": "mesh_points",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "fit cross section profiles to cut lines of a different length: stretch (default), clip, extend or none",
+                        "name": "reconcile",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "length difference below which profiles are not reconciled, 0.1 by default",
+                        "name": "reconcile_tolerance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated layers to extract, e.g. Rivers,XS",
+                        "name": "layers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated geometry files to extract, e.g. g02",
+                        "name": "geom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "minx,miny,maxx,maxy in the destination CRS, features outside are dropped",
+                        "name": "bbox",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "topology preserving simplification tolerance in destination CRS units",
+                        "name": "simplify",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of decimals of the x and y coordinates",
+                        "name": "precision",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CRS of the model coordinates as an EPSG code, WKT or PROJ string, for models without a projection or older than version 4.0",
+                        "name": "source_crs",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SimpleResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SimpleResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SimpleResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Extract geospatial data from a RAS model given an s3 key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MCAT"
+                ],
+                "summary": "Extract geospatial data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "/models/ras/CHURCH HOUSE GULLY/CHURCH HOUSE GULLY.prj",
+                        "name": "definition_file",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "destination CRS as an EPSG code, WKT or PROJ string, or native for the model projection, 4326 by default",
+                        "name": "crs",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) with WKB geometries, geojson for one FeatureCollection per layer, ndjson to stream one GeoJSON Feature per line, or a file format: gpkg, shapefile (zip), flatgeobuf (zip) or kml",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "new file store key under the export prefix to write file formats to instead of returning them, POST only",
+                        "name": "output_file",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "include 2D area cell centers",
+                        "name": "mesh_points",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "fit cross section profiles to cut lines of a different length: stretch (default), clip, extend or none",
+                        "name": "reconcile",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "length difference below which profiles are not reconciled, 0.1 by default",
+                        "name": "reconcile_tolerance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated layers to extract, e.g. Rivers,XS",
+                        "name": "layers",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated geometry files to extract, e.g. g02",
+                        "name": "geom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "minx,miny,maxx,maxy in the destination CRS, features outside are dropped",
+                        "name": "bbox",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "topology preserving simplification tolerance in destination CRS units",
+                        "name": "simplify",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of decimals of the x and y coordinates",
+                        "name": "precision",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "CRS of the model coordinates as an EPSG code, WKT or PROJ string, for models without a projection or older than version 4.0",
+                        "name": "source_crs",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -57,6 +287,18 @@ var doc = `{
                             "type": "object"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SimpleResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SimpleResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -266,6 +508,183 @@ var doc = `{
                     }
                 }
             }
+        },
+        "/topology": {
+            "get": {
+                "description": "Directed graph of reaches, storage areas and 2D areas of a geometry file with its headwater reaches, outlet reaches and disconnected components",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MCAT"
+                ],
+                "summary": "Topology of a geometry file",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "/models/ras/CHURCH HOUSE GULLY/CHURCH HOUSE GULLY.prj",
+                        "name": "definition_file",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "g01",
+                        "name": "geom",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tools.Topology"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SimpleResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topology/downstream": {
+            "get": {
+                "description": "Reaches, storage areas and 2D areas downstream of a river station",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MCAT"
+                ],
+                "summary": "Trace downstream of a river station",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "/models/ras/CHURCH HOUSE GULLY/CHURCH HOUSE GULLY.prj",
+                        "name": "definition_file",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "g01",
+                        "name": "geom",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Church House Gully",
+                        "name": "river",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reach 1",
+                        "name": "reach",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "5.99",
+                        "name": "rs",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tools.Trace"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SimpleResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/topology/upstream": {
+            "get": {
+                "description": "Reaches, storage areas and 2D areas upstream of a river station",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "MCAT"
+                ],
+                "summary": "Trace upstream of a river station",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "/models/ras/CHURCH HOUSE GULLY/CHURCH HOUSE GULLY.prj",
+                        "name": "definition_file",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "g01",
+                        "name": "geom",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Church House Gully",
+                        "name": "river",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reach 1",
+                        "name": "reach",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "5.99",
+                        "name": "rs",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/tools.Trace"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SimpleResponse"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
@@ -275,6 +694,9 @@ var doc = `{
                 "message": {
                     "type": "string"
                 },
+                "stackTrace": {
+                    "type": "string"
+                },
                 "status": {
                     "type": "integer"
                 }
@@ -360,6 +782,9 @@ var doc = `{
                 "definitionFile": {
                     "type": "string"
                 },
+                "definitionFileHash": {
+                    "type": "string"
+                },
                 "files": {
                     "$ref": "#/definitions/tools.ModelFiles"
                 },
@@ -430,6 +855,129 @@ var doc = `{
                     "type": "object"
                 }
             }
+        },
+        "tools.TopoComponent": {
+            "type": "object",
+            "properties": {
+                "disconnected": {
+                    "type": "boolean"
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "tools.TopoEdge": {
+            "type": "object",
+            "properties": {
+                "To RS": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rs": {
+                    "description": "river station of the structure on the From reach, only exists for lateral structures and pump stations",
+                    "type": "string"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "tools.TopoNode": {
+            "type": "object",
+            "properties": {
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "tools.Topology": {
+            "type": "object",
+            "properties": {
+                "Components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tools.TopoComponent"
+                    }
+                },
+                "Edges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tools.TopoEdge"
+                    }
+                },
+                "Geometry File": {
+                    "type": "string"
+                },
+                "Headwater Reaches": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "Nodes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/tools.TopoNode"
+                    }
+                },
+                "Outlet Reaches": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "tools.Trace": {
+            "type": "object",
+            "properties": {
+                "2D Areas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "Cross Sections": {
+                    "description": "cross sections of the starting reach on the traced side of RS",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "Storage Areas": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "direction": {
+                    "type": "string"
+                },
+                "reach": {
+                    "type": "string"
+                },
+                "reaches": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rs": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
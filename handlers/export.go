@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/USACE/filestore"
+	"github.com/ar-siddiqui/mcat-ras/config"
+	"github.com/ar-siddiqui/mcat-ras/tools"
+
+	"github.com/go-errors/errors" // warning: replaces standard errors
+	"github.com/labstack/echo/v4"
+)
+
+// Size of the chunks written to the file store, which writes chunk n at offset n times this size
+const uploadChunkSize = 10 * 1024 * 1024
+
+// Parse and validate the output_file query parameter. Writing to the file store requires a POST request
+// and a key under the export prefix which does not exist yet. Returns the HTTP status of a validation error.
+func outputFileQuery(c echo.Context, ac *config.APIConfig) (string, int, error) {
+	outputFile := c.QueryParam("output_file")
+	if outputFile == "" {
+		return "", http.StatusOK, nil
+	}
+
+	if c.Request().Method != http.MethodPost {
+		return "", http.StatusBadRequest, errors.New("Invalid query parameter: `output_file` requires a POST request")
+	}
+
+	if path.Clean(outputFile) != outputFile || !strings.HasPrefix(outputFile, ac.ExportPrefix) {
+		return "", http.StatusBadRequest, errors.New("Invalid query parameter: `output_file` must be a key under " + ac.ExportPrefix)
+	}
+
+	if f, err := (*ac.FileStore).GetObject(outputFile); err == nil {
+		f.Close()
+		return "", http.StatusConflict, errors.New(outputFile + " already exists")
+	}
+	return outputFile, http.StatusOK, nil
+}
+
+// Export geospatial data and return it as an attachment, or write it to the file store when an output file is given
+func exportData(c echo.Context, ac *config.APIConfig, data tools.GeoData, exporter tools.Exporter, definitionFile string, outputFile string) error {
+	tmpDir, err := ioutil.TempDir("", "mcat-ras-")
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, SimpleResponse{http.StatusInternalServerError, fmt.Sprintf("Go error encountered: %v", err.Error()), ""})
+	}
+	defer os.RemoveAll(tmpDir)
+
+	fileName := strings.TrimSuffix(filepath.Base(definitionFile), filepath.Ext(definitionFile)) + exporter.Extension()
+	exportPath := filepath.Join(tmpDir, fileName)
+	if err := exporter.Export(data, exportPath); err != nil {
+		return c.JSON(http.StatusInternalServerError, SimpleResponse{http.StatusInternalServerError, fmt.Sprintf("Go error encountered: %v", err.Error()), err.(*errors.Error).ErrorStack()})
+	}
+
+	f, err := os.Open(exportPath)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, SimpleResponse{http.StatusInternalServerError, fmt.Sprintf("Go error encountered: %v", err.Error()), ""})
+	}
+	defer f.Close()
+
+	c.Response().Header().Set(georeferenceSourceHeader, data.GeoreferenceSource)
+
+	if outputFile == "" {
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fileName))
+		return c.Stream(http.StatusOK, exporter.ContentType(), f)
+	}
+
+	if err := putObjectChunked(*ac.FileStore, outputFile, f); err != nil {
+		return c.JSON(http.StatusInternalServerError, SimpleResponse{http.StatusInternalServerError, fmt.Sprintf("Go error encountered: %v", err.Error()), err.(*errors.Error).ErrorStack()})
+	}
+	return c.JSON(http.StatusOK, outputFile)
+}
+
+// Upload a reader to the file store in chunks of the file store chunk size, without reading it into memory
+func putObjectChunked(fs filestore.FileStore, key string, r io.Reader) error {
+	upload, err := fs.InitializeObjectUpload(filestore.UploadConfig{ObjectPath: key})
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	chunk := make([]byte, uploadChunkSize)
+	chunkIDs := []string{}
+	for chunkID := int64(0); ; chunkID++ {
+		n, err := io.ReadFull(r, chunk)
+		if err == io.EOF && chunkID > 0 {
+			break
+		}
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return errors.Wrap(err, 0)
+		}
+
+		part, err := fs.WriteChunk(filestore.UploadConfig{ObjectPath: key, ChunkId: chunkID, UploadId: upload.ID, Data: chunk[:n]})
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		chunkIDs = append(chunkIDs, part.ID)
+
+		if n < len(chunk) {
+			break
+		}
+	}
+
+	if err := fs.CompleteObjectUpload(filestore.CompletedObjectUploadConfig{UploadId: upload.ID, ObjectPath: key, ChunkUploadIds: chunkIDs}); err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/USACE/filestore"
+	"github.com/ar-siddiqui/mcat-ras/config"
+	"github.com/labstack/echo/v4"
+)
+
+func TestOutputFileQuery(t *testing.T) {
+	exportPrefix := filepath.Join(t.TempDir(), "exports") + "/"
+	if err := os.MkdirAll(exportPrefix, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(exportPrefix+"existing.gpkg", []byte("existing export"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var fs filestore.FileStore = &filestore.BlockFS{}
+	ac := &config.APIConfig{FileStore: &fs, ExportPrefix: exportPrefix}
+
+	tests := []struct {
+		name       string
+		method     string
+		outputFile string
+		want       string
+		status     int
+	}{
+		{"no output file", http.MethodGet, "", "", http.StatusOK},
+		{"new key", http.MethodPost, exportPrefix + "model.gpkg", exportPrefix + "model.gpkg", http.StatusOK},
+		{"get request", http.MethodGet, exportPrefix + "model.gpkg", "", http.StatusBadRequest},
+		{"outside the prefix", http.MethodPost, "models/model.gpkg", "", http.StatusBadRequest},
+		{"escaping the prefix", http.MethodPost, exportPrefix + "../model.gpkg", "", http.StatusBadRequest},
+		{"existing key", http.MethodPost, exportPrefix + "existing.gpkg", "", http.StatusConflict},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/geospatialdata?format=gpkg&output_file="+url.QueryEscape(tc.outputFile), nil)
+			c := echo.New().NewContext(req, httptest.NewRecorder())
+
+			outputFile, status, err := outputFileQuery(c, ac)
+			if outputFile != tc.want || status != tc.status {
+				t.Errorf("got (%q, %d, %v), want (%q, %d)", outputFile, status, err, tc.want, tc.status)
+			}
+			if (err != nil) != (tc.status != http.StatusOK) {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestPutObjectChunked(t *testing.T) {
+	var fs filestore.FileStore = &filestore.BlockFS{}
+	dir := t.TempDir()
+
+	tests := []struct {
+		name string
+		size int
+	}{
+		{"empty", 0},
+		{"one chunk", 1000},
+		{"exactly one chunk", uploadChunkSize},
+		{"two chunks", uploadChunkSize + 1000},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			body := bytes.Repeat([]byte{'a', 'b', 'c'}, tc.size/3+1)[:tc.size]
+			key := filepath.Join(dir, "exports", tc.name+".gpkg")
+			if err := putObjectChunked(fs, key, bytes.NewReader(body)); err != nil {
+				t.Fatal(err)
+			}
+			got, err := ioutil.ReadFile(key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, body) {
+				t.Errorf("got %d bytes, want %d", len(got), len(body))
+			}
+		})
+	}
+}
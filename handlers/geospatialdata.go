@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/ar-siddiqui/mcat-ras/config"
@@ -23,48 +26,245 @@ import (
 // @Accept json
 // @Produce json
 // @Param definition_file query string true "/models/ras/CHURCH HOUSE GULLY/CHURCH HOUSE GULLY.prj"
+// @Param crs query string false "destination CRS as an EPSG code, WKT or PROJ string, or native for the model projection, 4326 by default"
+// @Param format query string false "json (default) with WKB geometries, geojson for one FeatureCollection per layer, ndjson to stream one GeoJSON Feature per line, or a file format: gpkg, shapefile (zip), flatgeobuf (zip) or kml"
+// @Param output_file query string false "new file store key under the export prefix to write file formats to instead of returning them, POST only"
+// @Param mesh_points query bool false "include 2D area cell centers"
+// @Param reconcile query string false "fit cross section profiles to cut lines of a different length: stretch (default), clip, extend or none"
+// @Param reconcile_tolerance query number false "length difference below which profiles are not reconciled, 0.1 by default"
+// @Param layers query string false "comma separated layers to extract, e.g. Rivers,XS"
+// @Param geom query string false "comma separated geometry files to extract, e.g. g02"
+// @Param bbox query string false "minx,miny,maxx,maxy in the destination CRS, features outside are dropped"
+// @Param simplify query number false "topology preserving simplification tolerance in destination CRS units"
+// @Param precision query int false "number of decimals of the x and y coordinates"
+// @Param source_crs query string false "CRS of the model coordinates as an EPSG code, WKT or PROJ string, for models without a projection or older than version 4.0"
 // @Success 200 {object} interface{}
+// @Failure 400 {object} SimpleResponse
+// @Failure 409 {object} SimpleResponse
 // @Failure 500 {object} SimpleResponse
 // @Router /geospatialdata [get]
+// @Router /geospatialdata [post]
 func GeospatialData(ac *config.APIConfig) echo.HandlerFunc {
 	return func(c echo.Context) error {
 
-		definitionFile := c.QueryParam("definition_file")
-		if definitionFile == "" {
-			return c.JSON(http.StatusBadRequest, "Missing query parameter: `definition_file`")
+		format := c.QueryParam("format")
+		exporter, isExport := tools.Exporters[format]
+		if format != "" && format != "json" && format != "geojson" && format != "ndjson" && !isExport {
+			return c.JSON(http.StatusBadRequest, "Invalid query parameter: `format` must be one of json, geojson, ndjson, gpkg, shapefile, flatgeobuf or kml")
 		}
 
-		if !isAModel(ac.FileStore, definitionFile) {
-			return c.JSON(http.StatusBadRequest, definitionFile+" is not a valid RAS prj file.")
+		definitionFile, crs, opts, err := geospatialQuery(c, ac)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, err.Error())
+		}
+
+		outputFile, status, err := outputFileQuery(c, ac)
+		if err != nil {
+			return c.JSON(status, err.Error())
+		}
+		if outputFile != "" && !isExport {
+			return c.JSON(http.StatusBadRequest, "Invalid query parameter: `output_file` requires a file format")
 		}
 
-		if !isGeospatial(definitionFile, *ac.FileStore) {
-			return c.JSON(http.StatusBadRequest, definitionFile+" is not geospatial.")
+		if format == "ndjson" {
+			return streamGeospatialData(c, ac, definitionFile, crs, opts)
 		}
 
-		data, err := geospatialData(definitionFile, ac.FileStore, ac.DestinationCRS)
+		data, err := geospatialData(definitionFile, ac.FileStore, crs, opts)
 		if err != nil {
 			return c.JSON(http.StatusInternalServerError, SimpleResponse{http.StatusInternalServerError, fmt.Sprintf("Go error encountered: %v", err.Error()), err.(*errors.Error).ErrorStack()})
 		}
 
+		if isExport {
+			return exportData(c, ac, data, exporter, definitionFile, outputFile)
+		}
+
+		if format == "geojson" {
+			geoJSONData, err := data.ToGeoJSON()
+			if err != nil {
+				return c.JSON(http.StatusInternalServerError, SimpleResponse{http.StatusInternalServerError, fmt.Sprintf("Go error encountered: %v", err.Error()), err.(*errors.Error).ErrorStack()})
+			}
+			return c.JSON(http.StatusOK, geoJSONData)
+		}
+
 		return c.JSON(http.StatusOK, data)
 	}
 }
 
-func geospatialData(definitionFile string, fs *filestore.FileStore, destinationCRS int) (tools.GeoData, error) {
-	gd := tools.GeoData{Features: make(map[string]tools.Features), Georeference: destinationCRS}
+// Response header marking whether the coordinates were georeferenced with the model projection or a user supplied CRS
+const georeferenceSourceHeader = "X-Georeference-Source"
 
-	mfiles, err := modFiles(definitionFile, *fs)
+// Stream the features as NDJSON while they are extracted, so that they are never all held in memory.
+// The model is checked before the response is committed, so that its errors are returned with an error status.
+// Errors during extraction are written as the last line.
+func streamGeospatialData(c echo.Context, ac *config.APIConfig, definitionFile string, crs string, opts tools.GeoOptions) error {
+	gd, sourceCRS, mfiles, err := newGeospatialData(definitionFile, ac.FileStore, crs, opts)
 	if err != nil {
-		return gd, errors.Wrap(err, 0)
+		return c.JSON(http.StatusInternalServerError, SimpleResponse{http.StatusInternalServerError, fmt.Sprintf("Go error encountered: %v", err.Error()), err.(*errors.Error).ErrorStack()})
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+	res.Header().Set(georeferenceSourceHeader, gd.GeoreferenceSource)
+	res.WriteHeader(http.StatusOK)
+
+	opts.Sink = tools.NDJSONSink(res, res.Flush)
+	if err := extractGeospatialData(&gd, mfiles, ac.FileStore, sourceCRS, crs, opts); err != nil {
+		log.Println("NDJSON stream of", definitionFile, "|", err)
+		return json.NewEncoder(res).Encode(map[string]string{"error": err.Error()})
+	}
+	return nil
+}
+
+// Parse and validate the query parameters shared by the geospatial data endpoints.
+// Returns the definition file, the destination CRS and the geospatial options.
+func geospatialQuery(c echo.Context, ac *config.APIConfig) (string, string, tools.GeoOptions, error) {
+	opts := tools.GeoOptions{}
+
+	definitionFile := c.QueryParam("definition_file")
+	if definitionFile == "" {
+		return "", "", opts, errors.New("Missing query parameter: `definition_file`")
+	}
+
+	if !isAModel(ac.FileStore, definitionFile) {
+		return "", "", opts, errors.New(definitionFile + " is not a valid RAS prj file.")
+	}
+
+	// a user supplied CRS georeferences models without a projection or older than version 4.0
+	opts.SourceCRS = c.QueryParam("source_crs")
+	if opts.SourceCRS != "" {
+		if _, err := tools.CRSWKT(opts.SourceCRS); err != nil {
+			return "", "", opts, errors.New("Invalid query parameter: `source_crs` must be an EPSG code, WKT or PROJ string")
+		}
+	} else if !isGeospatial(definitionFile, *ac.FileStore) {
+		return "", "", opts, errors.New(definitionFile + " is not geospatial.")
+	}
+
+	crs := c.QueryParam("crs")
+	if crs == "" {
+		crs = ac.DestinationCRS
+	}
+	if !tools.ValidCRS(crs) {
+		return "", "", opts, errors.New("Invalid query parameter: `crs` must be an EPSG code, WKT or PROJ string, or native")
+	}
+
+	if meshPoints := c.QueryParam("mesh_points"); meshPoints != "" {
+		var err error
+		opts.MeshPoints, err = strconv.ParseBool(meshPoints)
+		if err != nil {
+			return "", "", opts, errors.New("Invalid query parameter: `mesh_points` must be a boolean")
+		}
+	}
+
+	opts.Reconcile = c.QueryParam("reconcile")
+	if !tools.ValidReconcileMethod(opts.Reconcile) {
+		return "", "", opts, errors.New("Invalid query parameter: `reconcile` must be one of stretch, clip, extend or none")
+	}
+
+	if tolerance := c.QueryParam("reconcile_tolerance"); tolerance != "" {
+		var err error
+		opts.ReconcileTolerance, err = strconv.ParseFloat(tolerance, 64)
+		if err != nil || opts.ReconcileTolerance < 0 {
+			return "", "", opts, errors.New("Invalid query parameter: `reconcile_tolerance` must be a positive number")
+		}
+	}
+
+	if layers := c.QueryParam("layers"); layers != "" {
+		for _, layer := range strings.Split(layers, ",") {
+			layer = strings.TrimSpace(layer)
+			if !tools.ValidLayer(layer) {
+				return "", "", opts, errors.New("Invalid query parameter: `layers` contains unknown layer " + layer)
+			}
+			opts.Layers = append(opts.Layers, layer)
+		}
+	}
+
+	if geom := c.QueryParam("geom"); geom != "" {
+		for _, geomFile := range strings.Split(geom, ",") {
+			opts.GeomFiles = append(opts.GeomFiles, strings.TrimSpace(geomFile))
+		}
+	}
+
+	if bbox := c.QueryParam("bbox"); bbox != "" {
+		bboxData := strings.Split(bbox, ",")
+		if len(bboxData) != 4 {
+			return "", "", opts, errors.New("Invalid query parameter: `bbox` must be minx,miny,maxx,maxy")
+		}
+		opts.BBox = &[4]float64{}
+		for n, value := range bboxData {
+			v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				return "", "", opts, errors.New("Invalid query parameter: `bbox` must be minx,miny,maxx,maxy")
+			}
+			opts.BBox[n] = v
+		}
+	}
+
+	if simplify := c.QueryParam("simplify"); simplify != "" {
+		var err error
+		opts.Simplify, err = strconv.ParseFloat(simplify, 64)
+		if err != nil || opts.Simplify < 0 {
+			return "", "", opts, errors.New("Invalid query parameter: `simplify` must be a positive number")
+		}
+	}
+
+	if precision := c.QueryParam("precision"); precision != "" {
+		digits, err := strconv.Atoi(precision)
+		if err != nil || digits < 0 {
+			return "", "", opts, errors.New("Invalid query parameter: `precision` must be a positive integer")
+		}
+		opts.Precision = &digits
 	}
 
-	projecFile := strings.TrimSuffix(definitionFile, ".prj") + ".projection"
-	proj, err := getProjection(*fs, projecFile)
+	return definitionFile, crs, opts, nil
+}
+
+func geospatialData(definitionFile string, fs *filestore.FileStore, destinationCRS string, opts tools.GeoOptions) (tools.GeoData, error) {
+	gd, sourceCRS, mfiles, err := newGeospatialData(definitionFile, fs, destinationCRS, opts)
 	if err != nil {
 		return gd, errors.Wrap(err, 0)
 	}
 
+	if err := extractGeospatialData(&gd, mfiles, fs, sourceCRS, destinationCRS, opts); err != nil {
+		return gd, errors.Wrap(err, 0)
+	}
+	return gd, nil
+}
+
+// Read the model projection and units and check them against the source and destination CRS.
+// Returns the empty geospatial data, the source CRS and the model files.
+func newGeospatialData(definitionFile string, fs *filestore.FileStore, destinationCRS string, opts tools.GeoOptions) (tools.GeoData, string, []string, error) {
+	gd := tools.GeoData{Features: make(map[string]tools.Features)}
+
+	mfiles, err := modFiles(definitionFile, *fs)
+	if err != nil {
+		return gd, "", mfiles, errors.Wrap(err, 0)
+	}
+
+	// the projection file is not needed when the caller supplies the CRS
+	proj := ""
+	if opts.SourceCRS == "" {
+		projecFile := strings.TrimSuffix(definitionFile, ".prj") + ".projection"
+		proj, err = getProjection(*fs, projecFile)
+		if err != nil {
+			return gd, "", mfiles, errors.Wrap(err, 0)
+		}
+	}
+
+	units, err := tools.ProjectUnits(*fs, definitionFile)
+	if err != nil {
+		return gd, "", mfiles, errors.Wrap(err, 0)
+	}
+
+	gd, sourceCRS, err := tools.NewGeoData(proj, units, destinationCRS, opts)
+	if err != nil {
+		return gd, sourceCRS, mfiles, errors.Wrap(err, 0)
+	}
+	return gd, sourceCRS, mfiles, nil
+}
+
+// Extract the features of the geometry files among the model files
+func extractGeospatialData(gd *tools.GeoData, mfiles []string, fs *filestore.FileStore, sourceCRS string, destinationCRS string, opts tools.GeoOptions) error {
 	for _, fp := range mfiles {
 
 		ext := filepath.Ext(fp)
@@ -73,14 +273,14 @@ func geospatialData(definitionFile string, fs *filestore.FileStore, destinationC
 
 		case tools.RasRE.Geom.MatchString(ext):
 
-			if err := tools.GetGeospatialData(&gd, *fs, fp, proj, destinationCRS); err != nil {
-				return gd, errors.Wrap(err, 0)
+			if err := tools.GetGeospatialData(gd, *fs, fp, sourceCRS, destinationCRS, opts); err != nil {
+				return errors.Wrap(err, 0)
 			}
 
 		}
 	}
 
-	return gd, nil
+	return nil
 }
 
 func getProjection(fs filestore.FileStore, fn string) (string, error) {
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/USACE/filestore"
+	"github.com/ar-siddiqui/mcat-ras/config"
+	"github.com/ar-siddiqui/mcat-ras/tools"
+	"github.com/labstack/echo/v4"
+)
+
+func TestStreamGeospatialDataModelError(t *testing.T) {
+	dir := t.TempDir()
+	definitionFile := filepath.Join(dir, "model.prj")
+	if err := ioutil.WriteFile(definitionFile, []byte("Proj Title=Model\nEnglish Units\nGeom File=g01\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var fs filestore.FileStore = &filestore.BlockFS{}
+	ac := &config.APIConfig{FileStore: &fs}
+
+	req := httptest.NewRequest(http.MethodGet, "/geospatialdata?format=ndjson", nil)
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(req, rec)
+
+	// the model has no projection file
+	if err := streamGeospatialData(c, ac, definitionFile, "4326", tools.GeoOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+	if contentType := rec.Header().Get(echo.HeaderContentType); !strings.HasPrefix(contentType, echo.MIMEApplicationJSON) {
+		t.Errorf("got content type %q, want JSON", contentType)
+	}
+	response := SimpleResponse{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("got body %s, want a SimpleResponse: %v", rec.Body.String(), err)
+	}
+	if response.Status != http.StatusInternalServerError {
+		t.Errorf("got response %+v", response)
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	ras "github.com/ar-siddiqui/mcat-ras/tools"
+
+	"github.com/USACE/filestore"
+	"github.com/go-errors/errors" // warning: replaces standard errors
+	"github.com/labstack/echo/v4"
+)
+
+// Topology godoc
+// @Summary Topology of a geometry file
+// @Description Directed graph of reaches, storage areas and 2D areas of a geometry file with its headwater reaches, outlet reaches and disconnected components
+// @Tags MCAT
+// @Accept json
+// @Produce json
+// @Param definition_file query string true "/models/ras/CHURCH HOUSE GULLY/CHURCH HOUSE GULLY.prj"
+// @Param geom query string true "g01"
+// @Success 200 {object} ras.Topology
+// @Failure 500 {object} SimpleResponse
+// @Router /topology [get]
+func Topology(fs *filestore.FileStore) echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		definitionFile := c.QueryParam("definition_file")
+		if definitionFile == "" {
+			return c.JSON(http.StatusBadRequest, "Missing query parameter: `definition_file`")
+		}
+
+		geom := c.QueryParam("geom")
+		if geom == "" {
+			return c.JSON(http.StatusBadRequest, "Missing query parameter: `geom`")
+		}
+
+		rm, err := ras.NewRasModel(definitionFile, *fs)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, SimpleResponse{http.StatusInternalServerError, fmt.Sprintf("Go error encountered: %v", err.Error()), err.(*errors.Error).ErrorStack()})
+		}
+
+		topology, err := rm.Topology(geom)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, SimpleResponse{http.StatusBadRequest, err.Error(), err.(*errors.Error).ErrorStack()})
+		}
+
+		return c.JSON(http.StatusOK, topology)
+	}
+}
+
+// TraceUpstream godoc
+// @Summary Trace upstream of a river station
+// @Description Reaches, storage areas and 2D areas upstream of a river station
+// @Tags MCAT
+// @Accept json
+// @Produce json
+// @Param definition_file query string true "/models/ras/CHURCH HOUSE GULLY/CHURCH HOUSE GULLY.prj"
+// @Param geom query string true "g01"
+// @Param river query string true "Church House Gully"
+// @Param reach query string true "Reach 1"
+// @Param rs query string true "5.99"
+// @Success 200 {object} ras.Trace
+// @Failure 500 {object} SimpleResponse
+// @Router /topology/upstream [get]
+func TraceUpstream(fs *filestore.FileStore) echo.HandlerFunc {
+	return trace(fs, true)
+}
+
+// TraceDownstream godoc
+// @Summary Trace downstream of a river station
+// @Description Reaches, storage areas and 2D areas downstream of a river station
+// @Tags MCAT
+// @Accept json
+// @Produce json
+// @Param definition_file query string true "/models/ras/CHURCH HOUSE GULLY/CHURCH HOUSE GULLY.prj"
+// @Param geom query string true "g01"
+// @Param river query string true "Church House Gully"
+// @Param reach query string true "Reach 1"
+// @Param rs query string true "5.99"
+// @Success 200 {object} ras.Trace
+// @Failure 500 {object} SimpleResponse
+// @Router /topology/downstream [get]
+func TraceDownstream(fs *filestore.FileStore) echo.HandlerFunc {
+	return trace(fs, false)
+}
+
+func trace(fs *filestore.FileStore, upstream bool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+
+		for _, param := range []string{"definition_file", "geom", "river", "reach", "rs"} {
+			if c.QueryParam(param) == "" {
+				return c.JSON(http.StatusBadRequest, fmt.Sprintf("Missing query parameter: `%s`", param))
+			}
+		}
+
+		rm, err := ras.NewRasModel(c.QueryParam("definition_file"), *fs)
+		if err != nil {
+			return c.JSON(http.StatusInternalServerError, SimpleResponse{http.StatusInternalServerError, fmt.Sprintf("Go error encountered: %v", err.Error()), err.(*errors.Error).ErrorStack()})
+		}
+
+		topology, err := rm.Topology(c.QueryParam("geom"))
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, SimpleResponse{http.StatusBadRequest, err.Error(), err.(*errors.Error).ErrorStack()})
+		}
+
+		reach := fmt.Sprintf("%s, %s", c.QueryParam("river"), c.QueryParam("reach"))
+		result, err := topology.Trace(reach, c.QueryParam("rs"), upstream)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, SimpleResponse{http.StatusBadRequest, err.Error(), err.(*errors.Error).ErrorStack()})
+		}
+
+		return c.JSON(http.StatusOK, result)
+	}
+}
@@ -10,6 +10,15 @@ import (
 	// echoSwagger "github.com/swaggo/echo-swagger"
 )
 
+// @title RAS MCAT API
+// @version 1.0
+// @description API for the RAS MCAT
+// @termsOfService http://swagger.io/terms/
+
+// @contact.name API Support
+// @contact.email slawler@dewberry.com
+
+// @host localhost:5600
 func main() {
 
 	// Connect to backend services
@@ -37,7 +46,11 @@ func main() {
 	e.GET("/index", handlers.Index(appConfig.FileStore))
 	e.GET("/isgeospatial", handlers.IsGeospatial(appConfig.FileStore))
 	e.GET("/geospatialdata", handlers.GeospatialData(appConfig))
+	e.POST("/geospatialdata", handlers.GeospatialData(appConfig))
 	e.GET("/forcingdata", handlers.ForcingData(appConfig))
+	e.GET("/topology", handlers.Topology(appConfig.FileStore))
+	e.GET("/topology/upstream", handlers.TraceUpstream(appConfig.FileStore))
+	e.GET("/topology/downstream", handlers.TraceDownstream(appConfig.FileStore))
 
 	// pgdb endpoints
 	e.POST("/upsert/model", pgdb.UpsertRasModel(appConfig, dbConfig))
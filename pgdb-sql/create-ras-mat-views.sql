@@ -110,6 +110,7 @@ SELECT
     (metadata -> 'Culvert Data' ->> 'Num Culverts') AS num_culverts,
     (metadata-> 'Bridge Data' ->> 'Num Bridges') AS num_bridges,
     (metadata -> 'Inline Weir Data' ->> 'Num Inline Weirs') AS num_weirs,
+    (metadata -> 'Lateral Structure Data' ->> 'Num Lateral Structures') AS num_laterals,
     file_ext,
     s3_key
 FROM hydraulic_structures
@@ -33,9 +33,13 @@ CREATE TABLE IF NOT EXISTS models.ras_geometry_files(
        geometry_file_extension TEXT NOT NULL,
        geometry_title TEXT NOT NULL,
        geometry_program_version DECIMAL,
-       geometry_description TEXT
+       geometry_description TEXT,
+       georeference_source TEXT -- model or user-georeferenced
 );
 
+-- Add columns missing from databases created before they were introduced
+ALTER TABLE models.ras_geometry_files ADD COLUMN IF NOT EXISTS georeference_source TEXT;
+
 -- Create index on foreign key
 CREATE INDEX IF NOT EXISTS ras_geometry_files_ras_fk_idx ON models.ras_geometry_files (model_inventory_id);
 
@@ -96,6 +100,42 @@ CREATE INDEX IF NOT EXISTS ras_banks_xs_id_idx ON models.ras_banks (xs_id);
 CREATE INDEX IF NOT EXISTS ras_banks_geom_idx ON models.ras_banks USING GIST (geom);
 
 
+/*---------------------------------------------------------------------------*/
+-- Create models.ras_bank_lines table
+/*---------------------------------------------------------------------------*/
+CREATE TABLE IF NOT EXISTS models.ras_bank_lines(
+      bank_line_id SERIAL PRIMARY KEY,
+      river_id INTEGER REFERENCES models.ras_rivers ON UPDATE CASCADE ON DELETE CASCADE,
+      bank_side TEXT NOT NULL,
+      geom GEOMETRY(MultiLineString, 4326),
+      CONSTRAINT ras_bank_lines_river_id_bank_side UNIQUE (river_id, bank_side)
+);
+
+-- Create index on foreign key
+CREATE INDEX IF NOT EXISTS ras_bank_lines_river_id_idx ON models.ras_bank_lines (river_id);
+
+-- Create index on geometry
+CREATE INDEX IF NOT EXISTS ras_bank_lines_geom_idx ON models.ras_bank_lines USING GIST (geom);
+
+
+/*---------------------------------------------------------------------------*/
+-- Create models.ras_flow_paths table
+/*---------------------------------------------------------------------------*/
+CREATE TABLE IF NOT EXISTS models.ras_flow_paths(
+      flow_path_id SERIAL PRIMARY KEY,
+      river_id INTEGER REFERENCES models.ras_rivers ON UPDATE CASCADE ON DELETE CASCADE,
+      flow_path_type TEXT NOT NULL,
+      geom GEOMETRY(MultiLineString, 4326),
+      CONSTRAINT ras_flow_paths_river_id_flow_path_type UNIQUE (river_id, flow_path_type)
+);
+
+-- Create index on foreign key
+CREATE INDEX IF NOT EXISTS ras_flow_paths_river_id_idx ON models.ras_flow_paths (river_id);
+
+-- Create index on geometry
+CREATE INDEX IF NOT EXISTS ras_flow_paths_geom_idx ON models.ras_flow_paths USING GIST (geom);
+
+
 /*---------------------------------------------------------------------------*/
 -- Create models.ras_areas table
 /*---------------------------------------------------------------------------*/
@@ -45,7 +45,7 @@ func UpsertRasGeometry(ac *config.APIConfig, db *sqlx.DB) echo.HandlerFunc {
 					Message: "Missing query parameter: `definition_file`"})
 		}
 
-		err := upsertModelGeometry(definitionFile, ac, db)
+		err := upsertModelGeometry(definitionFile, c.QueryParam("source_crs"), ac, db)
 		if err != nil {
 			return c.JSON(http.StatusInternalServerError, handlers.SimpleResponse{Status: http.StatusInternalServerError, Message: fmt.Sprintf("Go error encountered: %v", err.Error()), StackTrace: err.(*errors.Error).ErrorStack()})
 		}
@@ -5,6 +5,9 @@ import (
 	"os"
 )
 
+// SRID of the geometry columns of the models schema, which geometries are transformed to before they are upserted
+const geometrySRID = "4326"
+
 var (
 	getCollectionIDSQL string = fmt.Sprintf(`
 	SELECT collection_id 
@@ -53,7 +56,7 @@ var (
 			reach_name, 
 			geom
 			) 
-		VALUES ($1, $2, $3, ST_GeomFromWKB($4, 4326))
+		VALUES ($1, $2, $3, ST_GeomFromWKB($4, ` + geometrySRID + `))
 		ON CONFLICT (
 			geometry_file_id,
 			river_name,
@@ -63,7 +66,7 @@ var (
 			geometry_file_id = $1, 
 			river_name = $2, 
 			reach_name = $3, 
-			geom = ST_GeomFromWKB($4, 4326)
+			geom = ST_GeomFromWKB($4, ` + geometrySRID + `)
 		RETURNING river_id;
 	`
 
@@ -74,7 +77,7 @@ var (
 			cut_line_profile_match, 
 			geom
 			) 
-		VALUES ($1, $2, $3, ST_GeomFromWKB($4, 4326))
+		VALUES ($1, $2, $3, ST_GeomFromWKB($4, ` + geometrySRID + `))
 		ON CONFLICT (
 			river_id,
 			xs_station
@@ -83,7 +86,7 @@ var (
 			river_id = $1, 
 			xs_station = $2, 
 			cut_line_profile_match = $3, 
-			geom = ST_GeomFromWKB($4, 4326)
+			geom = ST_GeomFromWKB($4, ` + geometrySRID + `)
 		RETURNING xs_id;
 	`
 
@@ -92,7 +95,7 @@ var (
 			xs_id, 
 			bank_station, 
 			geom) 
-		VALUES ($1, $2, ST_GeomFromWKB($3, 4326))
+		VALUES ($1, $2, ST_GeomFromWKB($3, ` + geometrySRID + `))
 		ON CONFLICT (
 			xs_id,
 			bank_station
@@ -100,7 +103,39 @@ var (
 		DO UPDATE SET 
 			xs_id = $1, 
 			bank_station = $2, 
-			geom = ST_GeomFromWKB($3, 4326);
+			geom = ST_GeomFromWKB($3, ` + geometrySRID + `);
+	`
+
+	upsertBankLinesSQL string = `
+		INSERT INTO models.ras_bank_lines (
+			river_id, 
+			bank_side, 
+			geom) 
+		VALUES ($1, $2, ST_GeomFromWKB($3, ` + geometrySRID + `))
+		ON CONFLICT (
+			river_id,
+			bank_side
+		)
+		DO UPDATE SET 
+			river_id = $1, 
+			bank_side = $2, 
+			geom = ST_GeomFromWKB($3, ` + geometrySRID + `);
+	`
+
+	upsertFlowPathsSQL string = `
+		INSERT INTO models.ras_flow_paths (
+			river_id, 
+			flow_path_type, 
+			geom) 
+		VALUES ($1, $2, ST_GeomFromWKB($3, ` + geometrySRID + `))
+		ON CONFLICT (
+			river_id,
+			flow_path_type
+		)
+		DO UPDATE SET 
+			river_id = $1, 
+			flow_path_type = $2, 
+			geom = ST_GeomFromWKB($3, ` + geometrySRID + `);
 	`
 
 	upsertAreasSQL string = `
@@ -110,13 +145,13 @@ var (
 			is2d,
 			geom
 			) 
-			VALUES ($1, $2, $3, ST_GeomFromWKB($4, 4326))
+			VALUES ($1, $2, $3, ST_GeomFromWKB($4, ` + geometrySRID + `))
 		ON CONFLICT (geometry_file_id, area_name)
 		DO UPDATE SET 
 			geometry_file_id = $1, 
 			area_name = $2,
 			is2d = $3,
-			geom = ST_GeomFromWKB($4, 4326)
+			geom = ST_GeomFromWKB($4, ` + geometrySRID + `)
 		RETURNING area_id;
 	`
 
@@ -128,14 +163,14 @@ var (
 			dn_area,
 			geom
 			) 
-			VALUES ($1, $2, $3, $4, ST_GeomFromWKB($5, 4326))
+			VALUES ($1, $2, $3, $4, ST_GeomFromWKB($5, ` + geometrySRID + `))
 		ON CONFLICT (geometry_file_id, connection_name)
 		DO UPDATE SET 
 			geometry_file_id = $1, 
 			connection_name = $2,
 			up_area = $3,
 			dn_area = $4,
-			geom = ST_GeomFromWKB($5, 4326);
+			geom = ST_GeomFromWKB($5, ` + geometrySRID + `);
 	`
 
 	upsertBreaklinesSQL string = `
@@ -144,12 +179,28 @@ var (
 		breakline_name, 
 		geom
 		) 
-		VALUES ($1, $2, ST_GeomFromWKB($3, 4326))
+		VALUES ($1, $2, ST_GeomFromWKB($3, ` + geometrySRID + `))
 	ON CONFLICT (geometry_file_id, breakline_name)
 	DO UPDATE SET 
 		geometry_file_id = $1, 
 		breakline_name = $2,
-		geom = ST_GeomFromWKB($3, 4326);
+		geom = ST_GeomFromWKB($3, ` + geometrySRID + `);
+	`
+
+	upsertHydraulicStructuresSQL string = `
+	INSERT INTO models.ras_hydraulic_structures (
+		geometry_file_id, 
+		hydraulic_structure_name, 
+		hydraulic_structure_type, 
+		geom
+		) 
+		VALUES ($1, $2, $3, ST_GeomFromWKB($4, ` + geometrySRID + `))
+	ON CONFLICT (geometry_file_id, hydraulic_structure_name, hydraulic_structure_type)
+	DO UPDATE SET 
+		geometry_file_id = $1, 
+		hydraulic_structure_name = $2,
+		hydraulic_structure_type = $3,
+		geom = ST_GeomFromWKB($4, ` + geometrySRID + `);
 	`
 
 	upsertBClinesSQL string = `
@@ -158,12 +209,12 @@ var (
 		bcline_name, 
 		geom
 		) 
-		VALUES ($1, $2, ST_GeomFromWKB($3, 4326))
+		VALUES ($1, $2, ST_GeomFromWKB($3, ` + geometrySRID + `))
 	ON CONFLICT (area_id, bcline_name)
 	DO UPDATE SET 
 		area_id = $1, 
 		bcline_name = $2,
-		geom = ST_GeomFromWKB($3, 4326);
+		geom = ST_GeomFromWKB($3, ` + geometrySRID + `);
 	`
 
 	upsertGeometrySQL string = `
@@ -173,9 +224,10 @@ var (
 			geometry_file_extension, 
 			geometry_title, 
 			geometry_program_version, 
-			geometry_description
+			geometry_description, 
+			georeference_source
 			) 
-		VALUES ($1, $2, $3, $4, $5, $6)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		ON CONFLICT (geometry_file_path)
 		DO UPDATE SET 
 			model_inventory_id = $1, 
@@ -183,7 +235,8 @@ var (
 			geometry_file_extension = $3, 
 			geometry_title = $4, 
 			geometry_program_version = $5, 
-			geometry_description = $6 
+			geometry_description = $6, 
+			georeference_source = $7 
 		RETURNING geometry_file_id;
 	`
 )
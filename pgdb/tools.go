@@ -121,7 +121,8 @@ func upsertModelInfo(definitionFile string, ac *config.APIConfig, db *sqlx.DB) e
 // Calls receiver function GeospatialData create geometry features.
 // Add records to multiple tables.
 // Expects model record already exist in model table.
-func upsertModelGeometry(definitionFile string, ac *config.APIConfig, db *sqlx.DB) error {
+// A source CRS georeferences models without a projection or older than version 4.0.
+func upsertModelGeometry(definitionFile string, sourceCRS string, ac *config.APIConfig, db *sqlx.DB) error {
 	ctx := context.Background()
 	tx, err := db.BeginTxx(ctx, nil)
 	defer tx.Rollback() // necessary so that transaction is not left idle if there are any errors
@@ -143,9 +144,9 @@ func upsertModelGeometry(definitionFile string, ac *config.APIConfig, db *sqlx.D
 		return errors.Wrap(err, 0)
 	}
 
-	if rm.IsGeospatial() {
+	if sourceCRS != "" || rm.IsGeospatial() {
 
-		geodata, err := rm.GeospatialData(ac.DestinationCRS)
+		geodata, err := rm.GeospatialData(geometrySRID, ras.GeoOptions{SourceCRS: sourceCRS})
 		if err != nil {
 			return errors.Wrap(err, 0)
 		}
@@ -166,7 +167,8 @@ func upsertModelGeometry(definitionFile string, ac *config.APIConfig, db *sqlx.D
 				geometryFile.FileExt,
 				geometryFile.GeomTitle,
 				version,
-				geometryFile.Description); err != nil {
+				geometryFile.Description,
+				geodata.GeoreferenceSource); err != nil {
 				log.Println("Geometry File", geometryFile.FileExt, "|", err)
 				return errors.Wrap(err, 0)
 			}
@@ -200,7 +202,11 @@ func upsertModelGeometry(definitionFile string, ac *config.APIConfig, db *sqlx.D
 					return errors.Wrap(err, 0)
 				}
 
-				riverID := riverIDMap[riverReach.(string)]
+				riverID, ok := riverIDMap[riverReach.(string)]
+				if !ok {
+					log.Println("XS", geometryFile.FileExt, "| skipping", xs.FeatureName, "of unknown river reach", riverReach)
+					continue
+				}
 				if err = tx.Get(&xsID, upsertXSSQL, riverID, xsStation, cutLineProfileMatch, xs.Geometry); err != nil {
 					log.Println(err)
 					return errors.Wrap(err, 0)
@@ -212,7 +218,11 @@ func upsertModelGeometry(definitionFile string, ac *config.APIConfig, db *sqlx.D
 			// Add all Banks
 			for _, banks := range features.Banks {
 				riverReachXSName := fmt.Sprintf("%s-%s", banks.Fields["RiverReachName"], banks.Fields["xsName"].(string))
-				xsID := xsIDMap[riverReachXSName]
+				xsID, ok := xsIDMap[riverReachXSName]
+				if !ok {
+					log.Println("Banks", geometryFile.FileExt, "| skipping", banks.FeatureName, "of unknown cross section", riverReachXSName)
+					continue
+				}
 				bankStation, err := strconv.ParseFloat(banks.FeatureName, 64)
 				if err != nil {
 					return errors.Wrap(err, 0)
@@ -225,6 +235,34 @@ func upsertModelGeometry(definitionFile string, ac *config.APIConfig, db *sqlx.D
 				}
 			}
 
+			// Add all Bank Lines
+			for _, bankLine := range features.BankLines {
+				riverID, ok := riverIDMap[bankLine.Fields["RiverReachName"].(string)]
+				if !ok {
+					log.Println("Bank Lines", geometryFile.FileExt, "| skipping", bankLine.FeatureName, "of unknown river reach", bankLine.Fields["RiverReachName"])
+					continue
+				}
+				_, err = tx.Exec(upsertBankLinesSQL, riverID, bankLine.FeatureName, bankLine.Geometry)
+				if err != nil {
+					log.Println("Bank Lines", geometryFile.FileExt, "|", err)
+					return errors.Wrap(err, 0)
+				}
+			}
+
+			// Add all Flow Paths
+			for _, flowPath := range features.FlowPaths {
+				riverID, ok := riverIDMap[flowPath.Fields["RiverReachName"].(string)]
+				if !ok {
+					log.Println("Flow Paths", geometryFile.FileExt, "| skipping", flowPath.FeatureName, "of unknown river reach", flowPath.Fields["RiverReachName"])
+					continue
+				}
+				_, err = tx.Exec(upsertFlowPathsSQL, riverID, flowPath.FeatureName, flowPath.Geometry)
+				if err != nil {
+					log.Println("Flow Paths", geometryFile.FileExt, "|", err)
+					return errors.Wrap(err, 0)
+				}
+			}
+
 			// Create Dynamic container to map bclines with areas
 			areasIDMap := make(map[string]int, (len(features.StorageAreas) + len(features.TwoDAreas)))
 
@@ -268,6 +306,15 @@ func upsertModelGeometry(definitionFile string, ac *config.APIConfig, db *sqlx.D
 				}
 			}
 
+			// Add all hydraulic structures
+			for _, hs := range features.HydraulicStructures {
+				_, err = tx.Exec(upsertHydraulicStructuresSQL, geometryFileID, hs.FeatureName, hs.Fields["Type"], hs.Geometry)
+				if err != nil {
+					log.Println("Hydraulic Structures", geometryFile.FileExt, "|", err)
+					return errors.Wrap(err, 0)
+				}
+			}
+
 			// Add all bounbdary condition lines
 			for _, bcl := range features.BCLines {
 				areaID := areasIDMap[bcl.Fields["Area"].(string)]
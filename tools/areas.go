@@ -3,27 +3,44 @@ package tools
 import (
 	"bufio"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
 	"github.com/go-errors/errors" // warning: replaces standard errors
 )
 
+// Map of HEC RAS Storage Area Type index to Storage Modes
+var storageModes map[string]string = map[string]string{
+	"0": "Area Times Depth",
+	"1": "Elevation-Volume"}
+
 type StorageArea struct {
-	NumBCLines int      `json:"Num BC Lines"`
-	BCLines    []string `json:"BC Lines"`
+	NumBCLines  int          `json:"Num BC Lines"`
+	BCLines     []string     `json:"BC Lines"`
+	Mode        string       `json:"Storage Mode"`
+	MinElev     float64      `json:"Min Elevation"`
+	Area        float64      `json:"Area"` // only used by area times depth storage areas
+	ElevVolume  [][2]float64 `json:"Elevation Volume"`
+	SurfaceLine [][2]float64 `json:"-"`
+	PlanarArea  float64      `json:"Planar Area"`
+	TopElev     float64      `json:"Top Elevation"`
+	Capacity    *float64     `json:"Capacity,omitempty"` // storage at top elevation, unknown for area times depth storage areas
 }
 
 type TwoDArea struct {
-	NumCells   int      `json:"Num Mesh Cells"`
-	NumBCLines int      `json:"Num BC Lines"`
-	BCLines    []string `json:"BC Lines"`
+	NumCells   int       `json:"Num Mesh Cells"`
+	NumBCLines int       `json:"Num BC Lines"`
+	BCLines    []string  `json:"BC Lines"`
+	MeshStats  MeshStats `json:"Mesh Statistics"`
 }
 
 // Extract Storage and 2D Areas Data
 func getAreasData(rm *RasModel, fn string, i int) (string, interface{}, error) {
 	var name, is2D string
 	var numCells int
+	var cellCenters [][2]float64
+	storageArea := StorageArea{}
 
 	f, err := rm.FileStore.GetObject(fn)
 	if err != nil {
@@ -45,6 +62,52 @@ areaLoop:
 			line := aSc.Text()
 			switch {
 
+			case strings.HasPrefix(line, "Storage Area Surface Line="):
+				nPairs, err := strconv.Atoi(rightofEquals(line))
+				if err != nil {
+					return "", nil, errors.Wrap(err, 0)
+				}
+				if nPairs == 0 {
+					continue
+				}
+				storageArea.SurfaceLine, err = dataPairsfromTextBlock(aSc, nPairs, 32, 16)
+				if err != nil {
+					return "", nil, errors.Wrap(err, 0)
+				}
+				ai += nPairs
+
+			case strings.HasPrefix(line, "Storage Area Type="):
+				storageArea.Mode = rightofEquals(line)
+				if mode, ok := storageModes[storageArea.Mode]; ok {
+					storageArea.Mode = mode
+				}
+
+			case strings.HasPrefix(line, "Storage Area Area="):
+				storageArea.Area, err = stringtoFloat(rightofEquals(line))
+				if err != nil {
+					return "", nil, errors.Wrap(err, 0)
+				}
+
+			case strings.HasPrefix(line, "Storage Area Min Elev="):
+				storageArea.MinElev, err = stringtoFloat(rightofEquals(line))
+				if err != nil {
+					return "", nil, errors.Wrap(err, 0)
+				}
+
+			case strings.HasPrefix(line, "Storage Area Vol Elev="):
+				nPairs, err := strconv.Atoi(rightofEquals(line))
+				if err != nil {
+					return "", nil, errors.Wrap(err, 0)
+				}
+				if nPairs == 0 {
+					continue
+				}
+				storageArea.ElevVolume, err = dataPairsfromTextBlock(aSc, nPairs, 80, 8)
+				if err != nil {
+					return "", nil, errors.Wrap(err, 0)
+				}
+				ai += numberofLines(nPairs*2, 80, 8)
+
 			case strings.HasPrefix(line, "Storage Area Is2D="):
 				is2D = rightofEquals(line)
 				if is2D != "0" && is2D != "-1" {
@@ -56,22 +119,36 @@ areaLoop:
 				if err != nil {
 					return "", nil, errors.Wrap(err, 0)
 				}
+				if numCells == 0 {
+					continue
+				}
+				cellCenters, err = dataPairsfromTextBlock(aSc, numCells, 64, 16)
+				if err != nil {
+					return "", nil, errors.Wrap(err, 0)
+				}
+				ai += numberofLines(numCells*2, 64, 16)
 
 			case strings.HasPrefix(line, "2D Face Area "):
 				break areaLoop
 
-			case strings.HasPrefix(line, "Storage Area="):
-				// guard to make sure new Storage Area don't overwrite previous values
+			case stringInSlice(leftofEquals(line), geomElementsPrefix[:]):
+				// guard to make sure new elements don't overwrite previous values
 				break areaLoop
 			}
 		}
 	}
-	if is2D == "0" {
-		area := StorageArea{}
-		return name, area, nil
-	} else {
+
+	switch is2D {
+	case "0", "":
+		// models older than HEC-RAS 5.0 have no 2D areas and do not flag storage areas
+		storageArea.PlanarArea = polygonArea(storageArea.SurfaceLine)
+		storageCapacity(&storageArea)
+		return name, storageArea, nil
+
+	case "-1":
 		area := TwoDArea{
-			NumCells: numCells,
+			NumCells:  numCells,
+			MeshStats: meshStatistics(cellCenters),
 		}
 		return name, area, nil
 	}
@@ -79,6 +156,39 @@ areaLoop:
 	return "", nil, errors.New(fmt.Sprintf("Failed to parse storage area at geom file line number %v of %v", i, fn))
 }
 
+// Planar area of a polygon using the shoelace formula
+func polygonArea(xyPairs [][2]float64) float64 {
+	var area float64
+	for n := range xyPairs {
+		next := xyPairs[(n+1)%len(xyPairs)]
+		area += xyPairs[n][0]*next[1] - next[0]*xyPairs[n][1]
+	}
+	return math.Abs(area) / 2
+}
+
+// Set top elevation and storage capacity at top elevation of a storage area.
+// Elevation-volume curves give the capacity at their highest elevation,
+// the capacity of area times depth storage areas is unbounded.
+func storageCapacity(storageArea *StorageArea) {
+	if len(storageArea.ElevVolume) == 0 {
+		storageArea.TopElev = storageArea.MinElev
+		return
+	}
+
+	top := storageArea.ElevVolume[0]
+	for _, pair := range storageArea.ElevVolume {
+		if pair[0] > top[0] {
+			top = pair
+		}
+	}
+	storageArea.TopElev = top[0]
+	if storageArea.Mode == "Area Times Depth" {
+		return
+	}
+	capacity := top[1]
+	storageArea.Capacity = &capacity
+}
+
 // Extract Boundary Condition Line Data
 func getBCLineData(rm *RasModel, fn string, i int) (string, string, error) {
 	var bc string
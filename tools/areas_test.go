@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPolygonArea(t *testing.T) {
+	tests := []struct {
+		name     string
+		xyPairs  [][2]float64
+		expected float64
+	}{
+		{"empty", [][2]float64{}, 0},
+		{"counterclockwise square", [][2]float64{{0, 0}, {10, 0}, {10, 10}, {0, 10}}, 100},
+		{"clockwise square", [][2]float64{{0, 0}, {0, 10}, {10, 10}, {10, 0}}, 100},
+		{"closed triangle", [][2]float64{{0, 0}, {4, 0}, {0, 3}, {0, 0}}, 6},
+		{"concave polygon", [][2]float64{{0, 0}, {4, 0}, {4, 4}, {2, 2}, {0, 4}}, 12},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if area := polygonArea(tc.xyPairs); area != tc.expected {
+				t.Errorf("got %v, want %v", area, tc.expected)
+			}
+		})
+	}
+}
+
+func TestStorageCapacity(t *testing.T) {
+	capacity := func(c float64) *float64 { return &c }
+
+	tests := []struct {
+		name     string
+		area     StorageArea
+		topElev  float64
+		capacity *float64
+	}{
+		{"no curve", StorageArea{Mode: "Area Times Depth", MinElev: 95, Area: 20}, 95, nil},
+		{"area times depth", StorageArea{Mode: "Area Times Depth", ElevVolume: [][2]float64{{95, 0}, {105, 200}}}, 105, nil},
+		{"elevation volume curve", StorageArea{Mode: "Elevation-Volume", ElevVolume: [][2]float64{{95, 0}, {100, 50}, {105, 200}}}, 105, capacity(200)},
+		{"unsorted curve", StorageArea{Mode: "Elevation-Volume", ElevVolume: [][2]float64{{100, 50}, {105, 200}, {95, 0}}}, 105, capacity(200)},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			storageArea := tc.area
+			storageCapacity(&storageArea)
+			if storageArea.TopElev != tc.topElev {
+				t.Errorf("got top elevation %v, want %v", storageArea.TopElev, tc.topElev)
+			}
+			if !reflect.DeepEqual(storageArea.Capacity, tc.capacity) {
+				t.Errorf("got capacity %v, want %v", storageArea.Capacity, tc.capacity)
+			}
+		})
+	}
+}
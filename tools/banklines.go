@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"sort"
+
+	"github.com/go-errors/errors" // warning: replaces standard errors
+)
+
+// Names of the flow path lines, from left overbank to right overbank
+var flowPathNames [3]string = [3]string{"Left Overbank", "Channel", "Right Overbank"}
+
+// Stations of the left and right bank of a cross section, relative to its first station
+func xsBankStations(xs crossSections) [2]float64 {
+	startingStation := xs.StationElevation[0][0]
+	return [2]float64{xs.LeftBank - startingStation, xs.RightBank - startingStation}
+}
+
+// Stations of the left overbank, channel and right overbank flow paths of a cross section, relative to its first station.
+// Each flow path is placed midway across its portion of the cross section.
+func xsFlowPathStations(xs crossSections) [3]float64 {
+	startingStation := xs.StationElevation[0][0]
+	endingStation := xs.StationElevation[len(xs.StationElevation)-1][0]
+	return [3]float64{
+		(startingStation+xs.LeftBank)/2 - startingStation,
+		(xs.LeftBank+xs.RightBank)/2 - startingStation,
+		(xs.RightBank+endingStation)/2 - startingStation,
+	}
+}
+
+// Create continuous bank lines and flow path lines of a reach by connecting cross section points in river station order.
+// Only cross sections with bank stations and whose profile fits their cut line are used.
+func getReachBankLines(reach reachGeometry, transform gisTransform, opts GeoOptions) ([]VectorFeature, []VectorFeature, error) {
+	bankLines, flowPaths := []VectorFeature{}, []VectorFeature{}
+
+	xss := []crossSections{}
+	for _, xs := range reach.XS {
+		if !xs.hasBanks() {
+			continue
+		}
+		if fittedXS, rec := reconcileXS(xs, opts); rec.hasProfile() {
+			xss = append(xss, fittedXS)
+		}
+	}
+	// from upstream to downstream
+	sort.Slice(xss, func(i, j int) bool { return xss[i].Station > xss[j].Station })
+
+	banks := [2][][2]float64{}
+	paths := [3][][2]float64{}
+	for _, xs := range xss {
+		for n, station := range xsBankStations(xs) {
+			banks[n] = append(banks[n], interpXY(xs.CutLine, station))
+		}
+		for n, station := range xsFlowPathStations(xs) {
+			paths[n] = append(paths[n], interpXY(xs.CutLine, station))
+		}
+	}
+
+	for n, side := range []string{"Left", "Right"} {
+		if len(banks[n]) < 2 {
+			continue
+		}
+		feature := VectorFeature{FeatureName: side, Fields: map[string]interface{}{}}
+		feature.Fields["RiverReachName"] = reach.Name
+		wkb, err := multiLineStringWKB(banks[n], transform)
+		if err != nil {
+			return bankLines, flowPaths, errors.Wrap(err, 0)
+		}
+		feature.Geometry = wkb
+		bankLines = append(bankLines, feature)
+	}
+
+	for n, name := range flowPathNames {
+		if len(paths[n]) < 2 {
+			continue
+		}
+		feature := VectorFeature{FeatureName: name, Fields: map[string]interface{}{}}
+		feature.Fields["RiverReachName"] = reach.Name
+		wkb, err := multiLineStringWKB(paths[n], transform)
+		if err != nil {
+			return bankLines, flowPaths, errors.Wrap(err, 0)
+		}
+		feature.Geometry = wkb
+		flowPaths = append(flowPaths, feature)
+	}
+	return bankLines, flowPaths, nil
+}
@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/dewberry/gdal"
+)
+
+// Identity transformation within a projected CRS, which keeps x and y in the easting, northing order
+func identityTransform(t *testing.T) gisTransform {
+	spRef := gdal.CreateSpatialReference("")
+	if err := spRef.FromEPSG(26918); err != nil {
+		t.Fatal(err)
+	}
+	return gisTransform{ct: gdal.CreateCoordinateTransform(spRef, spRef)}
+}
+
+// x and y pairs of the line strings of a multi line string WKB
+func wkbLines(t *testing.T, wkb []uint8) [][][2]float64 {
+	geom, err := gdal.CreateFromWKB(wkb, gdal.SpatialReference{}, len(wkb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer geom.Destroy()
+
+	lines := [][][2]float64{}
+	for i := 0; i < geom.GeometryCount(); i++ {
+		line := [][2]float64{}
+		lineString := geom.Geometry(i)
+		for j := 0; j < lineString.PointCount(); j++ {
+			x, y, _ := lineString.Point(j)
+			line = append(line, [2]float64{x, y})
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestGetReachBankLines(t *testing.T) {
+	xs := func(station float64, x float64, startingStation float64) crossSections {
+		return crossSections{
+			Station:          station,
+			StationElevation: [][2]float64{{startingStation, 10}, {startingStation + 50, 0}, {startingStation + 100, 10}},
+			LeftBank:         startingStation + 20,
+			RightBank:        startingStation + 80,
+			CutLine:          [][2]float64{{x, 0}, {x, 100}},
+			BankStations:     [2]string{fmt.Sprint(startingStation + 20), fmt.Sprint(startingStation + 80)},
+		}
+	}
+	withoutBanks := xs(150, 150, 0)
+	withoutBanks.BankStations = [2]string{}
+	reach := reachGeometry{
+		Name: "Creek, Upper",
+		XS: []crossSections{
+			xs(200, 100, 0),
+			xs(300, 0, 1000),
+			{Station: 250, StationElevation: [][2]float64{{0, 10}, {100, 10}}, LeftBank: 20, RightBank: 80},
+			xs(100, 200, 0),
+			withoutBanks,
+		},
+	}
+
+	bankLines, flowPaths, err := getReachBankLines(reach, identityTransform(t), GeoOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		features []VectorFeature
+		names    []string
+		lines    [][][2]float64
+	}{
+		{
+			bankLines, []string{"Left", "Right"},
+			[][][2]float64{
+				{{0, 20}, {100, 20}, {200, 20}},
+				{{0, 80}, {100, 80}, {200, 80}},
+			},
+		},
+		{
+			flowPaths, []string{"Left Overbank", "Channel", "Right Overbank"},
+			[][][2]float64{
+				{{0, 10}, {100, 10}, {200, 10}},
+				{{0, 50}, {100, 50}, {200, 50}},
+				{{0, 90}, {100, 90}, {200, 90}},
+			},
+		},
+	}
+	for _, tc := range tests {
+		if len(tc.features) != len(tc.names) {
+			t.Fatalf("got %d features, want %d", len(tc.features), len(tc.names))
+		}
+		for n, feature := range tc.features {
+			if feature.FeatureName != tc.names[n] {
+				t.Errorf("feature %d: got name %q, want %q", n, feature.FeatureName, tc.names[n])
+			}
+			if feature.Fields["RiverReachName"] != reach.Name {
+				t.Errorf("%s: got reach %v, want %q", feature.FeatureName, feature.Fields["RiverReachName"], reach.Name)
+			}
+			lines := wkbLines(t, feature.Geometry)
+			if !reflect.DeepEqual(lines, [][][2]float64{tc.lines[n]}) {
+				t.Errorf("%s: got %v, want %v", feature.FeatureName, lines, tc.lines[n])
+			}
+		}
+	}
+}
+
+func TestGetReachBankLinesSingleXS(t *testing.T) {
+	reach := reachGeometry{
+		Name: "Creek",
+		XS: []crossSections{{
+			Station:          100,
+			StationElevation: [][2]float64{{0, 10}, {100, 10}},
+			LeftBank:         20,
+			RightBank:        80,
+			CutLine:          [][2]float64{{0, 0}, {0, 100}},
+			BankStations:     [2]string{"20", "80"},
+		}},
+	}
+
+	bankLines, flowPaths, err := getReachBankLines(reach, identityTransform(t), GeoOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bankLines) != 0 || len(flowPaths) != 0 {
+		t.Errorf("got %d bank lines and %d flow paths, want none", len(bankLines), len(flowPaths))
+	}
+}
@@ -0,0 +1,279 @@
+package tools
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-errors/errors" // warning: replaces standard errors
+)
+
+// Map of HEC RAS Breach Method index to Breach Methods
+var breachMethods map[int]string = map[int]string{
+	0: "User Entered Data",
+	1: "Simplified Physical",
+	2: "Physically Based",
+	3: "MacDonald et al (1984)",
+	4: "Froehlich (1995)",
+	5: "Froehlich (2008)",
+	6: "Von Thun & Gillete (1990)",
+	7: "Xu & Zhang (2009)"}
+
+// Map of HEC RAS Breach Trigger index to Breach Triggers
+var breachTriggers map[int]string = map[int]string{
+	0: "WS Elev",
+	1: "WS Elev + Duration",
+	2: "Set Time"}
+
+// Store HEC-RAS Breach data of Inline Structures, Lateral Structures, and Connections.
+// Breaches are defined in plan files, not in geometry files.
+type breaches struct {
+	Plan              string // extension of the plan file defining the breach
+	Active            bool   // breach is turned on in the plan
+	Method            string
+	CenterStation     float64       `json:"Center Station"`
+	FinalBottomWidth  float64       `json:"Final Bottom Width"`
+	FinalBottomElev   float64       `json:"Final Bottom Elevation"`
+	LeftSideSlope     float64       `json:"Left Side Slope"`
+	RightSideSlope    float64       `json:"Right Side Slope"`
+	FormationTime     float64       `json:"Formation Time"`
+	BreachWeirCoef    float64       `json:"Breach Weir Coefficient"`
+	Trigger           breachTrigger `json:"Trigger"`
+	FailureMode       string        `json:"Failure Mode"`
+	PipingCoef        float64       `json:"Piping Coefficient"`
+	InitialPipingElev float64       `json:"Initial Piping Elevation"`
+}
+
+// Store the condition starting the breach
+type breachTrigger struct {
+	Type            string
+	WSElev          float64 `json:"WS Elevation"`
+	ThresholdWSElev float64 `json:"Threshold WS Elevation"`
+	Duration        float64 `json:"Duration Above Threshold"`
+	StartDate       string  `json:"Start Date"`
+	StartTime       string  `json:"Start Time"`
+}
+
+// Breach block of a plan file and the structure it applies to.
+// Inline and lateral structures are located by river, reach and river station, SA/2D connections by name only.
+type planBreach struct {
+	River         string
+	Reach         string
+	RS            string
+	StructureName string
+	Breach        breaches
+}
+
+// Add a line of a plan file to its breach blocks. Each block starts with a Breach Loc line followed by the breach lines,
+// lines before the first block are ignored.
+func addPlanBreachLine(planBreaches []planBreach, line string, planExt string) ([]planBreach, error) {
+	if strings.HasPrefix(line, "Breach Loc=") {
+		lineData := strings.Split(rightofEquals(line), ",")
+		return append(planBreaches, planBreach{
+			River:         lineValue(lineData, 0),
+			Reach:         lineValue(lineData, 1),
+			RS:            lineValue(lineData, 2),
+			StructureName: lineValue(lineData, 4),
+			Breach:        breaches{Plan: planExt, Active: strings.EqualFold(lineValue(lineData, 3), "True")},
+		}), nil
+	}
+	if len(planBreaches) == 0 {
+		return planBreaches, nil
+	}
+	if err := getBreachData(&planBreaches[len(planBreaches)-1].Breach, line); err != nil {
+		return planBreaches, errors.Wrap(err, 0)
+	}
+	return planBreaches, nil
+}
+
+// Attach the breaches of the plan files to the structures of the geometry files they use.
+// When several plans breach the same structure, an active breach is preferred, then the plan with the lowest extension.
+func attachBreaches(rm *RasModel) {
+	plans := make([]PlanFileContents, len(rm.Metadata.PlanFiles))
+	copy(plans, rm.Metadata.PlanFiles)
+	sort.Slice(plans, func(i, j int) bool { return plans[i].FileExt < plans[j].FileExt })
+
+	for _, p := range plans {
+		for g := range rm.Metadata.GeomFiles {
+			geom := &rm.Metadata.GeomFiles[g]
+			if strings.TrimPrefix(geom.FileExt, ".") != strings.TrimSpace(p.GeomFile) {
+				continue
+			}
+			for _, pb := range p.Breaches {
+				attachBreach(geom, pb)
+			}
+		}
+	}
+}
+
+// Check if a breach replaces the breach already attached to a structure
+func keepBreach(current *breaches, breach breaches) bool {
+	return current == nil || (!current.Active && breach.Active)
+}
+
+// Attach a plan breach to its structure of a geometry file. Breaches of structures missing from the geometry file are ignored.
+func attachBreach(geom *GeomFileContents, pb planBreach) {
+	breach := pb.Breach
+	if pb.River == "" && pb.Reach == "" {
+		if connection, ok := geom.Connections[pb.StructureName]; ok && keepBreach(connection.Breach, breach) {
+			connection.Breach = &breach
+			geom.Connections[pb.StructureName] = connection
+		}
+		return
+	}
+
+	station, err := stringtoFloat(strings.TrimRight(pb.RS, "*"))
+	if err != nil {
+		return
+	}
+	for s := range geom.Structures {
+		structures := &geom.Structures[s]
+		if structures.River != pb.River || structures.Reach != pb.Reach {
+			continue
+		}
+		for w := range structures.WeirData.Weirs {
+			if weir := &structures.WeirData.Weirs[w]; weir.Station == station {
+				if keepBreach(weir.Breach, breach) {
+					weir.Breach = &breach
+				}
+				return
+			}
+		}
+		for l := range structures.LateralData.Laterals {
+			if lateral := &structures.LateralData.Laterals[l]; lateral.Station == station {
+				if keepBreach(lateral.Breach, breach) {
+					lateral.Breach = &breach
+				}
+				return
+			}
+		}
+	}
+}
+
+// Extract data from a line of a breach block. Lines not describing the breach are ignored.
+func getBreachData(breach *breaches, line string) error {
+	var err error
+	switch {
+	case strings.HasPrefix(line, "Breach Method="):
+		breach.Method, err = getBreachMethod(line)
+
+	case strings.HasPrefix(line, "Breach Geom="):
+		err = getBreachGeom(breach, line)
+
+	case strings.HasPrefix(line, "Breach Start="):
+		breach.Trigger, err = getBreachTrigger(line)
+
+	case strings.HasPrefix(line, "Breach Piping="):
+		err = getBreachPiping(breach, line)
+	}
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+// Get the method used to estimate the breach
+func getBreachMethod(line string) (string, error) {
+	methodID, err := strconv.Atoi(strings.TrimSpace(rightofEquals(line)))
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	if method, ok := breachMethods[methodID]; ok {
+		return method, nil
+	}
+	return strconv.Itoa(methodID), nil
+}
+
+// Extract breach geometry from Breach Geom line.
+// Values are center station, final bottom width, final bottom elevation,
+// left side slope, right side slope, formation time and breach weir coefficient.
+func getBreachGeom(breach *breaches, line string) error {
+	lineData := strings.Split(rightofEquals(line), ",")
+
+	values := []*float64{
+		&breach.CenterStation,
+		&breach.FinalBottomWidth,
+		&breach.FinalBottomElev,
+		&breach.LeftSideSlope,
+		&breach.RightSideSlope,
+		&breach.FormationTime,
+		&breach.BreachWeirCoef,
+	}
+
+	n := 0
+	for _, value := range lineData {
+		if n >= len(values) {
+			break
+		}
+		// the flag preceding the weir coefficient is not a breach value
+		if v := strings.TrimSpace(value); strings.EqualFold(v, "True") || strings.EqualFold(v, "False") {
+			continue
+		}
+		val, err := stringtoFloat(value)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		*values[n] = val
+		n++
+	}
+	return nil
+}
+
+// Extract the breach trigger from Breach Start line.
+// Values are trigger type, trigger WS elevation, threshold WS elevation,
+// duration above threshold, start date and start time.
+func getBreachTrigger(line string) (breachTrigger, error) {
+	trigger := breachTrigger{}
+	lineData := strings.Split(rightofEquals(line), ",")
+
+	if triggerType := lineValue(lineData, 0); triggerType != "" {
+		triggerID, err := strconv.Atoi(triggerType)
+		if err != nil {
+			return trigger, errors.Wrap(err, 0)
+		}
+		if t, ok := breachTriggers[triggerID]; ok {
+			trigger.Type = t
+		} else {
+			trigger.Type = strconv.Itoa(triggerID)
+		}
+	}
+
+	values := []*float64{nil, &trigger.WSElev, &trigger.ThresholdWSElev, &trigger.Duration}
+	for n, v := range values {
+		if v == nil {
+			continue
+		}
+		val, err := stringtoFloat(lineValue(lineData, n))
+		if err != nil {
+			return trigger, errors.Wrap(err, 0)
+		}
+		*v = val
+	}
+	trigger.StartDate = lineValue(lineData, 4)
+	trigger.StartTime = lineValue(lineData, 5)
+	return trigger, nil
+}
+
+// Extract piping data from Breach Piping line.
+// Values are failure mode (-1 for piping, otherwise overtopping), piping coefficient and initial piping elevation.
+func getBreachPiping(breach *breaches, line string) error {
+	lineData := strings.Split(rightofEquals(line), ",")
+
+	breach.FailureMode = "Overtopping"
+	if lineValue(lineData, 0) == "-1" {
+		breach.FailureMode = "Piping"
+	}
+
+	pipingCoef, err := stringtoFloat(lineValue(lineData, 1))
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	breach.PipingCoef = pipingCoef
+
+	initialElev, err := stringtoFloat(lineValue(lineData, 2))
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	breach.InitialPipingElev = initialElev
+	return nil
+}
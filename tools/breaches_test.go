@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/USACE/filestore"
+)
+
+func TestGetPlanDataBreaches(t *testing.T) {
+	rm := RasModel{FileStore: &filestore.BlockFS{}}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	getPlanData(&rm, "testdata/breach.p01", &wg)
+
+	if len(rm.Metadata.PlanFiles) != 1 {
+		t.Fatalf("expected 1 plan file, got %d", len(rm.Metadata.PlanFiles))
+	}
+	plan := rm.Metadata.PlanFiles[0]
+	if plan.GeomFile != "g01" {
+		t.Errorf("expected geometry file g01, got %q", plan.GeomFile)
+	}
+
+	expected := []planBreach{
+		{
+			River: "Bald Eagle Cr.", Reach: "Lock Haven", RS: "81084.18", StructureName: "Sayers Dam",
+			Breach: breaches{
+				Plan: ".p01", Active: true, Method: "User Entered Data",
+				CenterStation: 1800, FinalBottomWidth: 500, FinalBottomElev: 590, LeftSideSlope: 1, RightSideSlope: 1,
+				FormationTime: 2.5, BreachWeirCoef: 2.6,
+				Trigger:     breachTrigger{Type: "WS Elev", WSElev: 660},
+				FailureMode: "Piping", PipingCoef: 0.5, InitialPipingElev: 610,
+			},
+		},
+		{
+			StructureName: "Dam",
+			Breach: breaches{
+				Plan: ".p01", Active: false, Method: "Froehlich (1995)",
+				CenterStation: 2100, FinalBottomWidth: 200, FinalBottomElev: 505, LeftSideSlope: 0.5, RightSideSlope: 0.5,
+				FormationTime: 1, BreachWeirCoef: 2.6,
+				Trigger:     breachTrigger{Type: "Set Time", StartDate: "02JAN1999", StartTime: "1300"},
+				FailureMode: "Overtopping", PipingCoef: 0.5,
+			},
+		},
+	}
+	if !reflect.DeepEqual(plan.Breaches, expected) {
+		t.Errorf("unexpected breaches\n got: %+v\nwant: %+v", plan.Breaches, expected)
+	}
+}
+
+func TestAttachBreaches(t *testing.T) {
+	inline := planBreach{River: "Bald Eagle Cr.", Reach: "Lock Haven", RS: "81084.18", Breach: breaches{Plan: ".p02", Method: "User Entered Data"}}
+	lateral := planBreach{River: "Bald Eagle Cr.", Reach: "Lock Haven", RS: "75000*", Breach: breaches{Plan: ".p01", Active: true}}
+	connection := planBreach{StructureName: "Dam", Breach: breaches{Plan: ".p01"}}
+	activeInline := inline
+	activeInline.Breach = breaches{Plan: ".p03", Active: true, Method: "Froehlich (2008)"}
+	missing := planBreach{River: "Other", Reach: "Reach", RS: "10", Breach: breaches{Plan: ".p01"}}
+
+	rm := RasModel{}
+	rm.Metadata.GeomFiles = []GeomFileContents{
+		{
+			FileExt: ".g01",
+			Structures: []hydraulicStructures{{
+				River: "Bald Eagle Cr.", Reach: "Lock Haven",
+				WeirData:    weirData{NumWeirs: 1, Weirs: []weirs{{Station: 81084.18}}},
+				LateralData: lateralData{NumLaterals: 1, Laterals: []laterals{{Station: 75000}}},
+			}},
+			Connections: map[string]Connection{"Dam": {}},
+		},
+		{FileExt: ".g02", Connections: map[string]Connection{"Dam": {}}},
+	}
+	rm.Metadata.PlanFiles = []PlanFileContents{
+		{FileExt: ".p03", GeomFile: "g01", Breaches: []planBreach{activeInline}},
+		{FileExt: ".p02", GeomFile: "g01", Breaches: []planBreach{inline, missing}},
+		{FileExt: ".p01", GeomFile: "g01", Breaches: []planBreach{lateral, connection}},
+	}
+
+	attachBreaches(&rm)
+
+	geom := rm.Metadata.GeomFiles[0]
+	if weir := geom.Structures[0].WeirData.Weirs[0]; weir.Breach == nil || weir.Breach.Plan != ".p03" {
+		t.Errorf("expected the active breach of .p03 on the inline structure, got %+v", weir.Breach)
+	}
+	if lat := geom.Structures[0].LateralData.Laterals[0]; lat.Breach == nil || lat.Breach.Plan != ".p01" {
+		t.Errorf("expected the breach of .p01 on the lateral structure, got %+v", lat.Breach)
+	}
+	if conn := geom.Connections["Dam"]; conn.Breach == nil || conn.Breach.Plan != ".p01" {
+		t.Errorf("expected the breach of .p01 on the connection, got %+v", conn.Breach)
+	}
+	if conn := rm.Metadata.GeomFiles[1].Connections["Dam"]; conn.Breach != nil {
+		t.Errorf("expected no breach on a geometry file without plan breaches, got %+v", conn.Breach)
+	}
+}
@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-errors/errors" // warning: replaces standard errors
+)
+
+// Store HEC-RAS 2D Breaklines
+type BreakLine struct {
+	NearSpacing      float64      `json:"Near Cell Spacing"`
+	FarSpacing       float64      `json:"Far Cell Spacing"`
+	NearRepeats      int          `json:"Near Repeats"`
+	ProtectionRadius float64      `json:"Protection Radius"`
+	Enforce1D        bool         `json:"Enforce 1D"`
+	Polyline         [][2]float64 `json:"-"`
+}
+
+// Store HEC-RAS 2D Refinement Regions
+type RefinementRegion struct {
+	CellSizeX        float64      `json:"Cell Size X"`
+	CellSizeY        float64      `json:"Cell Size Y"`
+	NearRepeats      int          `json:"Near Repeats"`
+	ProtectionRadius float64      `json:"Protection Radius"`
+	Polygon          [][2]float64 `json:"-"`
+}
+
+// Extract Breakline data from a Breakline text block.
+// Returns when a new geometry element is encountered, in which case the next scan must be skipped.
+func getBreakLineData(sc *bufio.Scanner) (string, BreakLine, bool, error) {
+	breakLine := BreakLine{}
+	name := rightofEquals(sc.Text())
+
+	for sc.Scan() {
+		line := sc.Text()
+		if stringInSlice(leftofEquals(line), geomElementsPrefix[:]) {
+			// a new HEC RAS element has been encountered, skip next scan and return
+			return name, breakLine, true, nil
+		}
+
+		var err error
+		switch {
+		case strings.HasPrefix(line, "BreakLine CellSize Min="):
+			breakLine.NearSpacing, err = stringtoFloat(rightofEquals(line))
+
+		case strings.HasPrefix(line, "BreakLine CellSize Max="):
+			breakLine.FarSpacing, err = stringtoFloat(rightofEquals(line))
+
+		case strings.HasPrefix(line, "BreakLine Near Repeats="):
+			breakLine.NearRepeats, err = atoiOrZero(rightofEquals(line))
+
+		case strings.HasPrefix(line, "BreakLine Protection Radius="):
+			breakLine.ProtectionRadius, err = stringtoFloat(rightofEquals(line))
+
+		case strings.HasPrefix(line, "BreakLine Enforce 1D="):
+			breakLine.Enforce1D = rightofEquals(line) == "-1"
+
+		case strings.HasPrefix(line, "BreakLine Polyline="):
+			breakLine.Polyline, err = polylineFromTextBlock(sc, line)
+		}
+		if err != nil {
+			return name, breakLine, false, errors.Wrap(err, 0)
+		}
+	}
+	return name, breakLine, false, nil
+}
+
+// Extract Refinement Region data from a Refinement Region text block.
+// Returns when a new geometry element is encountered, in which case the next scan must be skipped.
+func getRefinementRegionData(sc *bufio.Scanner) (string, RefinementRegion, bool, error) {
+	region := RefinementRegion{}
+	name := rightofEquals(sc.Text())
+
+	for sc.Scan() {
+		line := sc.Text()
+		if stringInSlice(leftofEquals(line), geomElementsPrefix[:]) {
+			// a new HEC RAS element has been encountered, skip next scan and return
+			return name, region, true, nil
+		}
+
+		var err error
+		switch {
+		case strings.HasPrefix(line, "Refinement Region CellSize X="):
+			region.CellSizeX, err = stringtoFloat(rightofEquals(line))
+
+		case strings.HasPrefix(line, "Refinement Region CellSize Y="):
+			region.CellSizeY, err = stringtoFloat(rightofEquals(line))
+
+		case strings.HasPrefix(line, "Refinement Region Near Repeats="):
+			region.NearRepeats, err = atoiOrZero(rightofEquals(line))
+
+		case strings.HasPrefix(line, "Refinement Region Protection Radius="):
+			region.ProtectionRadius, err = stringtoFloat(rightofEquals(line))
+
+		case strings.HasPrefix(line, "Refinement Region Polygon="):
+			region.Polygon, err = polylineFromTextBlock(sc, line)
+		}
+		if err != nil {
+			return name, region, false, errors.Wrap(err, 0)
+		}
+	}
+	return name, region, false, nil
+}
+
+// Read the coordinates following a polyline or polygon count line
+func polylineFromTextBlock(sc *bufio.Scanner, line string) ([][2]float64, error) {
+	nPairs, err := atoiOrZero(rightofEquals(line))
+	if err != nil {
+		return [][2]float64{}, errors.Wrap(err, 0)
+	}
+	if nPairs == 0 {
+		return [][2]float64{}, nil
+	}
+	return dataPairsfromTextBlock(sc, nPairs, 64, 16)
+}
+
+func atoiOrZero(s string) (int, error) {
+	if strings.TrimSpace(s) == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(strings.TrimSpace(s))
+}
+
+// Extract Breaklines Data
+func getBreakLinesData(rm *RasModel, fn string, i int) (string, BreakLine, error) {
+	f, err := rm.FileStore.GetObject(fn)
+	if err != nil {
+		return "", BreakLine{}, errors.Wrap(err, 0)
+	}
+	defer f.Close()
+
+	bSc := bufio.NewScanner(f)
+
+	bi := 0
+	for bSc.Scan() {
+		bi++
+		if bi == i {
+			name, breakLine, _, err := getBreakLineData(bSc)
+			if err != nil {
+				return name, breakLine, errors.Wrap(err, 0)
+			}
+			return name, breakLine, nil
+		}
+	}
+	return "", BreakLine{}, errors.New(fmt.Sprintf("Failed to parse breakline at geom file line number %v of %v", i, fn))
+}
+
+// Extract Refinement Regions Data
+func getRefinementRegionsData(rm *RasModel, fn string, i int) (string, RefinementRegion, error) {
+	f, err := rm.FileStore.GetObject(fn)
+	if err != nil {
+		return "", RefinementRegion{}, errors.Wrap(err, 0)
+	}
+	defer f.Close()
+
+	rSc := bufio.NewScanner(f)
+
+	ri := 0
+	for rSc.Scan() {
+		ri++
+		if ri == i {
+			name, region, _, err := getRefinementRegionData(rSc)
+			if err != nil {
+				return name, region, errors.Wrap(err, 0)
+			}
+			return name, region, nil
+		}
+	}
+	return "", RefinementRegion{}, errors.New(fmt.Sprintf("Failed to parse refinement region at geom file line number %v of %v", i, fn))
+}
@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetBreakLineData(t *testing.T) {
+	text := strings.Join([]string{
+		"BreakLine Name=Levee Crest",
+		"BreakLine CellSize Min=25",
+		"BreakLine CellSize Max=100",
+		"BreakLine Near Repeats=2",
+		"BreakLine Protection Radius=1",
+		"BreakLine Enforce 1D=-1",
+		"BreakLine Polyline=3",
+		"               0               0             100               0",
+		"             100              50",
+		"Refinement Region Name=Channel",
+	}, "\n")
+	sc := bufio.NewScanner(strings.NewReader(text))
+	sc.Scan()
+	name, breakLine, skipScan, err := getBreakLineData(sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := BreakLine{
+		NearSpacing: 25, FarSpacing: 100, NearRepeats: 2, ProtectionRadius: 1, Enforce1D: true,
+		Polyline: [][2]float64{{0, 0}, {100, 0}, {100, 50}},
+	}
+	if name != "Levee Crest" || !reflect.DeepEqual(breakLine, expected) {
+		t.Errorf("got %q %+v, want %q %+v", name, breakLine, "Levee Crest", expected)
+	}
+	if !skipScan || sc.Text() != "Refinement Region Name=Channel" {
+		t.Errorf("expected the next element to be left to the caller, scanner at %q", sc.Text())
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected BreakLine
+	}{
+		{"defaults", "BreakLine Name=Road\nBreakLine CellSize Min=\nBreakLine Near Repeats=\nBreakLine Enforce 1D=0\nBreakLine Polyline=0", BreakLine{Polyline: [][2]float64{}}},
+		{"no polyline", "BreakLine Name=Road\nBreakLine CellSize Max=50", BreakLine{FarSpacing: 50}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sc := bufio.NewScanner(strings.NewReader(tc.text))
+			sc.Scan()
+			_, breakLine, skipScan, err := getBreakLineData(sc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if skipScan || !reflect.DeepEqual(breakLine, tc.expected) {
+				t.Errorf("got %+v (skip %v), want %+v", breakLine, skipScan, tc.expected)
+			}
+		})
+	}
+
+	sc = bufio.NewScanner(strings.NewReader("BreakLine Name=Road\nBreakLine Near Repeats=two"))
+	sc.Scan()
+	if _, _, _, err := getBreakLineData(sc); err == nil {
+		t.Error("expected an error for a non numeric number of repeats")
+	}
+}
+
+func TestGetRefinementRegionData(t *testing.T) {
+	text := strings.Join([]string{
+		"Refinement Region Name=Channel",
+		"Refinement Region CellSize X=20",
+		"Refinement Region CellSize Y=40",
+		"Refinement Region Near Repeats=1",
+		"Refinement Region Protection Radius=0",
+		"Refinement Region Polygon=4",
+		"               0               0             100               0",
+		"             100              50               0              50",
+		"Storage Area=Pond,,",
+	}, "\n")
+	sc := bufio.NewScanner(strings.NewReader(text))
+	sc.Scan()
+	name, region, skipScan, err := getRefinementRegionData(sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := RefinementRegion{
+		CellSizeX: 20, CellSizeY: 40, NearRepeats: 1,
+		Polygon: [][2]float64{{0, 0}, {100, 0}, {100, 50}, {0, 50}},
+	}
+	if name != "Channel" || !reflect.DeepEqual(region, expected) {
+		t.Errorf("got %q %+v, want %q %+v", name, region, "Channel", expected)
+	}
+	if !skipScan || sc.Text() != "Storage Area=Pond,," {
+		t.Errorf("expected the next element to be left to the caller, scanner at %q", sc.Text())
+	}
+
+	sc = bufio.NewScanner(strings.NewReader("Refinement Region Name=Channel\nRefinement Region CellSize X=wide"))
+	sc.Scan()
+	if _, _, _, err := getRefinementRegionData(sc); err == nil {
+		t.Error("expected an error for a non numeric cell size")
+	}
+}
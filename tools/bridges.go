@@ -0,0 +1,303 @@
+package tools
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+
+	"github.com/go-errors/errors" // warning: replaces standard errors
+)
+
+// Map of HEC RAS Bridge Low Flow Method index to Low Flow Methods
+var lowFlowMethods map[int]string = map[int]string{
+	0: "Energy",
+	1: "Momentum",
+	2: "Yarnell",
+	3: "WSPRO"}
+
+// Map of HEC RAS Bridge High Flow Method index to High Flow Methods
+var highFlowMethods map[int]string = map[int]string{
+	0: "Energy Only",
+	1: "Pressure and/or Weir"}
+
+// Store Station, High Chord and Low Chord tables of a bridge deck/roadway face
+type deckTable struct {
+	Station   []float64
+	HighChord []float64 `json:"High Chord"`
+	LowChord  []float64 `json:"Low Chord"`
+}
+
+// Store HEC-RAS Bridge Piers. Widths vary with elevation
+type piers struct {
+	Skew           float64
+	UpStation      float64   `json:"Upstream Centerline Station"`
+	DownStation    float64   `json:"Downstream Centerline Station"`
+	UpWidths       []float64 `json:"Upstream Widths"`
+	UpElevations   []float64 `json:"Upstream Elevations"`
+	DownWidths     []float64 `json:"Downstream Widths"`
+	DownElevations []float64 `json:"Downstream Elevations"`
+}
+
+// Store HEC-RAS Bridge Sloping Abutments
+type abutments struct {
+	Skew           float64
+	UpStations     []float64 `json:"Upstream Stations"`
+	UpElevations   []float64 `json:"Upstream Elevations"`
+	DownStations   []float64 `json:"Downstream Stations"`
+	DownElevations []float64 `json:"Downstream Elevations"`
+}
+
+// Store HEC-RAS Bridge Modeling Approach
+type bridgeApproach struct {
+	LowFlowMethods      []string `json:"Low Flow Methods"`
+	LowFlowMethodUsed   string   `json:"Low Flow Method Used"`
+	MomentumDragCoef    float64  `json:"Momentum Drag Coefficient"`
+	YarnellK            float64  `json:"Yarnell K"`
+	HighFlowMethod      string   `json:"High Flow Method"`
+	SubmergedInletCd    float64  `json:"Submerged Inlet Coefficient"`
+	SubmergedInOutletCd float64  `json:"Submerged Inlet and Outlet Coefficient"`
+	MaxLowChord         float64  `json:"Max Low Chord"`
+}
+
+// Return a series of nValues values from a HEC-RAS text block, blank values are returned as nil.
+// The line index is incremented by the number of lines read.
+func nullableSeriesFromTextBlock(sc *bufio.Scanner, i int, nValues int, colWidth int, valueWidth int) ([]*float64, int, error) {
+	series := []*float64{}
+	if nValues == 0 {
+		return series, i, nil
+	}
+
+	textValues, err := parseSeriesTextBlock(sc, nValues, colWidth, valueWidth)
+	if err != nil {
+		return series, i, errors.Wrap(err, 0)
+	}
+	i += numberofLines(nValues, colWidth, valueWidth)
+
+	for _, text := range textValues {
+		if text == "" {
+			series = append(series, nil)
+			continue
+		}
+		val, err := parseFloat(text, 64)
+		if err != nil {
+			return series, i, errors.Wrap(err, 0)
+		}
+		series = append(series, &val)
+	}
+	return series, i, nil
+}
+
+// Return a series of nValues values from a HEC-RAS text block, blank values are returned as 0.
+func floatSeriesFromTextBlock(sc *bufio.Scanner, i int, nValues int) ([]float64, int, error) {
+	nullable, i, err := nullableSeriesFromTextBlock(sc, i, nValues, 80, 8)
+	if err != nil {
+		return []float64{}, i, errors.Wrap(err, 0)
+	}
+	return denull(nullable), i, nil
+}
+
+func denull(nullable []*float64) []float64 {
+	series := make([]float64, len(nullable))
+	for n, v := range nullable {
+		if v != nil {
+			series[n] = *v
+		}
+	}
+	return series
+}
+
+// Maximum and minimum of non blank values
+func nullableMaxMin(nullable []*float64) (maxMinPairs, error) {
+	values := []float64{}
+	for _, v := range nullable {
+		if v != nil {
+			values = append(values, *v)
+		}
+	}
+	if len(values) == 0 {
+		return maxMinPairs{}, nil
+	}
+
+	maxElev, err := maxValue(values)
+	if err != nil {
+		return maxMinPairs{}, errors.Wrap(err, 0)
+	}
+	minElev, err := minValue(values)
+	if err != nil {
+		return maxMinPairs{}, errors.Wrap(err, 0)
+	}
+	return maxMinPairs{Max: maxElev, Min: minElev}, nil
+}
+
+// Extract the station, high chord and low chord blocks of one face of a bridge deck.
+// Also returns the maximum and minimum of the high chord and low chord.
+func getDeckTable(hsSc *bufio.Scanner, i int, nElevText string) (deckTable, [2]maxMinPairs, int, error) {
+	table := deckTable{}
+	highLowPairs := [2]maxMinPairs{}
+
+	nElev, err := strconv.Atoi(strings.TrimSpace(nElevText))
+	if err != nil {
+		return table, highLowPairs, i, errors.Wrap(err, 0)
+	}
+
+	stations, i, err := nullableSeriesFromTextBlock(hsSc, i, nElev, 80, 8)
+	if err != nil {
+		return table, highLowPairs, i, errors.Wrap(err, 0)
+	}
+	highChord, i, err := nullableSeriesFromTextBlock(hsSc, i, nElev, 80, 8)
+	if err != nil {
+		return table, highLowPairs, i, errors.Wrap(err, 0)
+	}
+	lowChord, i, err := nullableSeriesFromTextBlock(hsSc, i, nElev, 80, 8)
+	if err != nil {
+		return table, highLowPairs, i, errors.Wrap(err, 0)
+	}
+	table = deckTable{Station: denull(stations), HighChord: denull(highChord), LowChord: denull(lowChord)}
+
+	highLowPairs[0], err = nullableMaxMin(highChord)
+	if err != nil {
+		return table, highLowPairs, i, errors.Wrap(err, 0)
+	}
+	highLowPairs[1], err = nullableMaxMin(lowChord)
+	if err != nil {
+		return table, highLowPairs, i, errors.Wrap(err, 0)
+	}
+	return table, highLowPairs, i, nil
+}
+
+// Extract pier data. The pier line holds skew, upstream centerline station and number of widths,
+// downstream centerline station and number of widths. It is followed by the upstream widths and elevations
+// and the downstream widths and elevations blocks.
+func getPier(hsSc *bufio.Scanner, i int, line string) (piers, int, error) {
+	pier := piers{}
+	pierData := strings.Split(rightofEquals(line), ",")
+	if len(pierData) < 5 {
+		return pier, i, errors.Errorf("Failed to parse pier at line '%s'", line)
+	}
+
+	values := []*float64{&pier.Skew, &pier.UpStation, nil, &pier.DownStation}
+	for n, v := range values {
+		if v == nil {
+			continue
+		}
+		val, err := stringtoFloat(pierData[n])
+		if err != nil {
+			return pier, i, errors.Wrap(err, 0)
+		}
+		*v = val
+	}
+
+	nUp, err := strconv.Atoi(strings.TrimSpace(pierData[2]))
+	if err != nil {
+		return pier, i, errors.Wrap(err, 0)
+	}
+	nDown, err := strconv.Atoi(strings.TrimSpace(pierData[4]))
+	if err != nil {
+		return pier, i, errors.Wrap(err, 0)
+	}
+
+	blocks := []struct {
+		series *[]float64
+		n      int
+	}{{&pier.UpWidths, nUp}, {&pier.UpElevations, nUp}, {&pier.DownWidths, nDown}, {&pier.DownElevations, nDown}}
+	for _, block := range blocks {
+		*block.series, i, err = floatSeriesFromTextBlock(hsSc, i, block.n)
+		if err != nil {
+			return pier, i, errors.Wrap(err, 0)
+		}
+	}
+	return pier, i, nil
+}
+
+// Extract sloping abutment data. The abutment line holds skew and the number of upstream and downstream points.
+// It is followed by the upstream stations and elevations and the downstream stations and elevations blocks.
+func getAbutment(hsSc *bufio.Scanner, i int, line string) (abutments, int, error) {
+	abutment := abutments{}
+	abutmentData := strings.Split(rightofEquals(line), ",")
+	if len(abutmentData) < 3 {
+		return abutment, i, errors.Errorf("Failed to parse abutment at line '%s'", line)
+	}
+
+	skew, err := stringtoFloat(abutmentData[0])
+	if err != nil {
+		return abutment, i, errors.Wrap(err, 0)
+	}
+	abutment.Skew = skew
+
+	nUp, err := strconv.Atoi(strings.TrimSpace(abutmentData[1]))
+	if err != nil {
+		return abutment, i, errors.Wrap(err, 0)
+	}
+	nDown, err := strconv.Atoi(strings.TrimSpace(abutmentData[2]))
+	if err != nil {
+		return abutment, i, errors.Wrap(err, 0)
+	}
+
+	blocks := []struct {
+		series *[]float64
+		n      int
+	}{{&abutment.UpStations, nUp}, {&abutment.UpElevations, nUp}, {&abutment.DownStations, nDown}, {&abutment.DownElevations, nDown}}
+	for _, block := range blocks {
+		*block.series, i, err = floatSeriesFromTextBlock(hsSc, i, block.n)
+		if err != nil {
+			return abutment, i, errors.Wrap(err, 0)
+		}
+	}
+	return abutment, i, nil
+}
+
+// Extract the bridge modeling approach from BR Coef line.
+// Values are the flags of low flow methods to compute (energy, momentum, Yarnell, WSPRO),
+// low flow method used (0 for highest energy answer, otherwise the method index plus one),
+// momentum drag coefficient, Yarnell K, high flow method, submerged inlet coefficient,
+// submerged inlet and outlet coefficient and max low chord.
+func getBridgeApproach(line string) (bridgeApproach, error) {
+	approach := bridgeApproach{LowFlowMethods: []string{}}
+	coefData := strings.Split(rightofEquals(line), ",")
+
+	for n := 0; n < 4; n++ {
+		if flag := lineValue(coefData, n); flag != "" && flag != "0" {
+			approach.LowFlowMethods = append(approach.LowFlowMethods, lowFlowMethods[n])
+		}
+	}
+
+	if used := lineValue(coefData, 4); used != "" {
+		usedID, err := strconv.Atoi(used)
+		if err != nil {
+			return approach, errors.Wrap(err, 0)
+		}
+		if method, ok := lowFlowMethods[usedID-1]; ok {
+			approach.LowFlowMethodUsed = method
+		} else {
+			approach.LowFlowMethodUsed = "Highest Energy Answer"
+		}
+	}
+
+	if highFlow := lineValue(coefData, 7); highFlow != "" {
+		highFlowID, err := strconv.Atoi(highFlow)
+		if err != nil {
+			return approach, errors.Wrap(err, 0)
+		}
+		if method, ok := highFlowMethods[highFlowID]; ok {
+			approach.HighFlowMethod = method
+		} else {
+			approach.HighFlowMethod = strconv.Itoa(highFlowID)
+		}
+	}
+
+	values := map[int]*float64{
+		5:  &approach.MomentumDragCoef,
+		6:  &approach.YarnellK,
+		8:  &approach.SubmergedInletCd,
+		9:  &approach.SubmergedInOutletCd,
+		10: &approach.MaxLowChord,
+	}
+	for idx, v := range values {
+		val, err := stringtoFloat(lineValue(coefData, idx))
+		if err != nil {
+			return approach, errors.Wrap(err, 0)
+		}
+		*v = val
+	}
+	return approach, nil
+}
@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetBridgeApproach(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected bridgeApproach
+	}{
+		{
+			"highest energy answer", "BR Coef=-1,-1,0,0,0,1.33,.8,1,0.5,0.8,105",
+			bridgeApproach{
+				LowFlowMethods: []string{"Energy", "Momentum"}, LowFlowMethodUsed: "Highest Energy Answer", MomentumDragCoef: 1.33,
+				YarnellK: 0.8, HighFlowMethod: "Pressure and/or Weir", SubmergedInletCd: 0.5, SubmergedInOutletCd: 0.8, MaxLowChord: 105,
+			},
+		},
+		{
+			"selected method", "BR Coef=-1,0,-1,-1,3,2,.9,0,,,",
+			bridgeApproach{
+				LowFlowMethods: []string{"Energy", "Yarnell", "WSPRO"}, LowFlowMethodUsed: "Yarnell", MomentumDragCoef: 2,
+				YarnellK: 0.9, HighFlowMethod: "Energy Only",
+			},
+		},
+		{
+			"unknown high flow method", "BR Coef=0,0,0,0,,,,5",
+			bridgeApproach{LowFlowMethods: []string{}, HighFlowMethod: "5"},
+		},
+		{
+			"no values", "BR Coef=",
+			bridgeApproach{LowFlowMethods: []string{}},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			approach, err := getBridgeApproach(tc.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(approach, tc.expected) {
+				t.Errorf("got %+v, want %+v", approach, tc.expected)
+			}
+		})
+	}
+
+	if _, err := getBridgeApproach("BR Coef=-1,0,0,0,first"); err == nil {
+		t.Error("expected an error for a non numeric low flow method")
+	}
+}
@@ -19,6 +19,7 @@ type Connection struct {
 	Gates       []gates
 	NumConduits int        `json:"Num Culvert Conduits"`
 	Conduits    []conduits `json:"Culvert Conduits"`
+	Breach      *breaches  `json:"Breach,omitempty"`
 }
 
 // Extract data from Connections
@@ -78,11 +79,17 @@ func getConnectionsData(rm *RasModel, fn string, i int) (string, Connection, err
 				connection.WeirElev = elev
 
 			case strings.HasPrefix(line, "Conn Gate Name Wd,H,"):
+				ci++
 				cSc.Scan()
 				gate, err := getGates(cSc.Text())
 				if err != nil {
 					return name, connection, errors.Wrap(err, 0)
 				}
+				nLines, err := getGateOpenings(cSc, &gate)
+				ci += nLines
+				if err != nil {
+					return name, connection, errors.Wrap(err, 0)
+				}
 				connection.Gates = append(connection.Gates, gate)
 				connection.NumGates++
 
@@ -0,0 +1,276 @@
+package tools
+
+import (
+	"bufio"
+	"strconv"
+	"strings"
+
+	"github.com/go-errors/errors" // warning: replaces standard errors
+)
+
+// Store HEC-RAS 1D Cross Sections
+type crossSections struct {
+	Name             string       `json:"River Station"`
+	Station          float64      `json:"Station"`
+	Interpolated     bool         `json:"Interpolated"`
+	Description      string       `json:"Description"`
+	LOBLength        float64      `json:"LOB Reach Length"`
+	ChannelLength    float64      `json:"Channel Reach Length"`
+	ROBLength        float64      `json:"ROB Reach Length"`
+	StationElevation [][2]float64 `json:"Station Elevation"`
+	ManningsN        [][2]float64 `json:"Mannings N"` // pairs of starting station and n value
+	LeftBank         float64      `json:"Left Bank Station"`
+	RightBank        float64      `json:"Right Bank Station"`
+	Contraction      float64      `json:"Contraction Coefficient"`
+	Expansion        float64      `json:"Expansion Coefficient"`
+	Skew             float64      `json:"Skew Angle"`
+	IneffectiveAreas []xsBlocks   `json:"Ineffective Flow Areas"`
+	Obstructions     []xsBlocks   `json:"Blocked Obstructions"`
+	LeftLevee        *levees      `json:"Left Levee,omitempty"`
+	RightLevee       *levees      `json:"Right Levee,omitempty"`
+	CutLine          [][2]float64 `json:"-"` // only used to create geospatial features
+	BankStations     [2]string    `json:"-"` // bank stations as written in the geometry file, empty without Bank Sta line
+}
+
+// Check if the bank stations of a cross section were parsed
+func (xs crossSections) hasBanks() bool {
+	return xs.BankStations[0] != "" && xs.BankStations[1] != ""
+}
+
+// Store HEC-RAS Ineffective Flow Areas and Blocked Obstructions of a Cross Section
+type xsBlocks struct {
+	StartStation float64 `json:"Start Station"`
+	EndStation   float64 `json:"End Station"`
+	Elevation    float64
+	Permanent    bool
+}
+
+// Store HEC-RAS Levees of a Cross Section
+type levees struct {
+	Station   float64
+	Elevation float64
+}
+
+// Get Ineffective Flow Areas or Blocked Obstructions of a cross section.
+// Each block is written as a triplet of start station, end station, and elevation.
+func getXSBlocks(sc *bufio.Scanner, i int, line string) ([]xsBlocks, int, error) {
+	blocks := []xsBlocks{}
+
+	nBlocks, err := strconv.Atoi(strings.TrimSpace(strings.Split(rightofEquals(line), ",")[0]))
+	if err != nil {
+		return blocks, i, errors.Wrap(err, 0)
+	}
+	if nBlocks == 0 {
+		return blocks, i, nil
+	}
+
+	series, err := seriesFromTextBlock(sc, nBlocks*3, 72, 8)
+	if err != nil {
+		return blocks, i, errors.Wrap(err, 0)
+	}
+	i += numberofLines(nBlocks*3, 72, 8)
+
+	for t := 0; t+2 < len(series); t += 3 {
+		blocks = append(blocks, xsBlocks{StartStation: series[t], EndStation: series[t+1], Elevation: series[t+2]})
+	}
+	return blocks, i, nil
+}
+
+// Get Levees of a cross section.
+// Levee line contains a flag, station, and elevation for the left levee followed by the same for the right levee.
+func getLevees(line string) (*levees, *levees, error) {
+	var left, right *levees
+	leveeData := strings.Split(rightofEquals(line), ",")
+
+	for n, l := range []**levees{&left, &right} {
+		if len(leveeData) < n*3+3 || strings.TrimSpace(leveeData[n*3]) != "-1" {
+			continue
+		}
+		station, err := stringtoFloat(leveeData[n*3+1])
+		if err != nil {
+			return left, right, errors.Wrap(err, 0)
+		}
+		elevation, err := stringtoFloat(leveeData[n*3+2])
+		if err != nil {
+			return left, right, errors.Wrap(err, 0)
+		}
+		*l = &levees{Station: station, Elevation: elevation}
+	}
+	return left, right, nil
+}
+
+// Get Manning's n values of a cross section. Each value is stored
+// with the station at which it starts.
+func getManningsN(sc *bufio.Scanner, i int, line string) ([][2]float64, int, error) {
+	nValues := [][2]float64{}
+
+	nMann, err := strconv.Atoi(strings.TrimSpace(strings.Split(rightofEquals(line), ",")[0]))
+	if err != nil {
+		return nValues, i, errors.Wrap(err, 0)
+	}
+	if nMann == 0 {
+		return nValues, i, nil
+	}
+
+	// values are written in triplets of station, n value, and a placeholder
+	series, err := seriesFromTextBlock(sc, nMann*3, 72, 8)
+	if err != nil {
+		return nValues, i, errors.Wrap(err, 0)
+	}
+	i += numberofLines(nMann*3, 72, 8)
+
+	for t := 0; t+1 < len(series); t += 3 {
+		nValues = append(nValues, [2]float64{series[t], series[t+1]})
+	}
+	return nValues, i, nil
+}
+
+// Extract data from HEC-RAS 1D Cross Sections.
+// Returns when a new geometry element is encountered, in which case the next scan must be skipped.
+func getXSData(sc *bufio.Scanner, i int, lineData []string) (crossSections, int, bool, error) {
+	xs := crossSections{Name: strings.TrimSpace(lineData[1])}
+	xs.Interpolated = strings.Contains(xs.Name, "*")
+
+	numericStation, err := toNumeric(xs.Name)
+	if err != nil {
+		return xs, i, false, errors.Wrap(err, 0)
+	}
+	xs.Station, err = parseFloat(numericStation, 64)
+	if err != nil {
+		return xs, i, false, errors.Wrap(err, 0)
+	}
+
+	reachLengths := [3]float64{}
+	for n := range reachLengths {
+		if len(lineData) > n+2 {
+			reachLengths[n], err = stringtoFloat(lineData[n+2])
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+		}
+	}
+	xs.LOBLength, xs.ChannelLength, xs.ROBLength = reachLengths[0], reachLengths[1], reachLengths[2]
+
+	for sc.Scan() {
+		i++
+		line := sc.Text()
+		if stringInSlice(leftofEquals(line), geomElementsPrefix[:]) {
+			// a new HEC RAS element has been encountered, skip next scan and return
+			return xs, i, true, nil
+		}
+
+		switch {
+		case strings.HasPrefix(line, "BEGIN DESCRIPTION"):
+			var description string
+			description, i, err = getDescription(sc, i, "END DESCRIPTION:")
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+			xs.Description += description
+
+		case strings.HasPrefix(line, "XS GIS Cut Line="):
+			nPairs, err := strconv.Atoi(rightofEquals(line))
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+			if nPairs == 0 {
+				continue
+			}
+			xs.CutLine, err = dataPairsfromTextBlock(sc, nPairs, 64, 16)
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+			i += numberofLines(nPairs*2, 64, 16)
+
+		case strings.HasPrefix(line, "#Sta/Elev="):
+			nPairs, err := strconv.Atoi(rightofEquals(line))
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+			if nPairs == 0 {
+				continue
+			}
+			xs.StationElevation, err = dataPairsfromTextBlock(sc, nPairs, 80, 8)
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+			i += numberofLines(nPairs*2, 80, 8)
+
+		case strings.HasPrefix(line, "#Mann="):
+			xs.ManningsN, i, err = getManningsN(sc, i, line)
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+
+		case strings.HasPrefix(line, "Bank Sta="):
+			bankStations := strings.Split(rightofEquals(line), ",")
+			if len(bankStations) < 2 {
+				return xs, i, false, errors.Errorf("Failed to parse bank stations at line '%s'", line)
+			}
+			xs.LeftBank, err = stringtoFloat(bankStations[0])
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+			xs.RightBank, err = stringtoFloat(bankStations[1])
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+			xs.BankStations = [2]string{strings.TrimSpace(bankStations[0]), strings.TrimSpace(bankStations[1])}
+
+		case strings.HasPrefix(line, "Exp/Cntr="):
+			coefficients := strings.Split(rightofEquals(line), ",")
+			xs.Expansion, err = stringtoFloat(coefficients[0])
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+			if len(coefficients) > 1 {
+				xs.Contraction, err = stringtoFloat(coefficients[1])
+				if err != nil {
+					return xs, i, false, errors.Wrap(err, 0)
+				}
+			}
+
+		case strings.HasPrefix(line, "#XS Ineff="):
+			xs.IneffectiveAreas, i, err = getXSBlocks(sc, i, line)
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+
+		case strings.HasPrefix(line, "Permanent Ineff="):
+			nAreas := len(xs.IneffectiveAreas)
+			if nAreas == 0 {
+				continue
+			}
+			permanent, err := parseSeriesTextBlock(sc, nAreas, 80, 8)
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+			i += numberofLines(nAreas, 80, 8)
+			for n, p := range permanent {
+				xs.IneffectiveAreas[n].Permanent = p == "T"
+			}
+
+		case strings.HasPrefix(line, "#Block Obstruct="):
+			xs.Obstructions, i, err = getXSBlocks(sc, i, line)
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+			for n := range xs.Obstructions {
+				xs.Obstructions[n].Permanent = true
+			}
+
+		case strings.HasPrefix(line, "Levee="):
+			xs.LeftLevee, xs.RightLevee, err = getLevees(line)
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+
+		case strings.HasPrefix(line, "Skew Angle="):
+			xs.Skew, err = stringtoFloat(rightofEquals(line))
+			if err != nil {
+				return xs, i, false, errors.Wrap(err, 0)
+			}
+		}
+	}
+	return xs, i, false, nil
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetXSBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		line     string
+		expected []xsBlocks
+		lines    int
+		next     string
+	}{
+		{"no blocks", "Bank Sta=10,90", "#Block Obstruct= 0 ,0", []xsBlocks{}, 0, ""},
+		{
+			"one block", "       0      20     105\nBank Sta=10,90", "#Block Obstruct= 1 ,0",
+			[]xsBlocks{{StartStation: 0, EndStation: 20, Elevation: 105}}, 1, "Bank Sta=10,90",
+		},
+		{
+			"blocks spanning several lines",
+			"       0      20     105      80     100   106.5       0      10     104\n      90     100     104\nBank Sta=10,90",
+			"#XS Ineff= 4 ,0",
+			[]xsBlocks{
+				{StartStation: 0, EndStation: 20, Elevation: 105},
+				{StartStation: 80, EndStation: 100, Elevation: 106.5},
+				{StartStation: 0, EndStation: 10, Elevation: 104},
+				{StartStation: 90, EndStation: 100, Elevation: 104},
+			},
+			2, "Bank Sta=10,90",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sc := bufio.NewScanner(strings.NewReader(tc.text))
+			blocks, i, err := getXSBlocks(sc, 0, tc.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(blocks, tc.expected) {
+				t.Errorf("got %+v, want %+v", blocks, tc.expected)
+			}
+			if i != tc.lines {
+				t.Errorf("read %d lines, want %d", i, tc.lines)
+			}
+			if tc.next != "" && (!sc.Scan() || sc.Text() != tc.next) {
+				t.Errorf("scanner at %q, want %q", sc.Text(), tc.next)
+			}
+		})
+	}
+
+	sc := bufio.NewScanner(strings.NewReader(""))
+	if _, _, err := getXSBlocks(sc, 0, "#XS Ineff= many ,0"); err == nil {
+		t.Error("expected an error for a non numeric number of blocks")
+	}
+}
+
+func TestGetXSData(t *testing.T) {
+	text := strings.Join([]string{
+		"BEGIN DESCRIPTION:",
+		"Upstream face",
+		"of the bridge",
+		"END DESCRIPTION:",
+		"XS GIS Cut Line=2",
+		"               0               0             100               0",
+		"#Sta/Elev= 3 ",
+		"       0     110      50     100     100     110",
+		"#Mann= 3 , 0 , 0 ",
+		"       0     .06       0      40    .035       0      60     .06       0",
+		"Bank Sta=40,60",
+		"Levee=-1,30,112,0,,",
+		"Exp/Cntr=0.3,0.1",
+		"#XS Ineff= 1 ,0 ",
+		"       0      30     108",
+		"Permanent Ineff=",
+		"       T",
+		"#Block Obstruct= 1 ,0 ",
+		"      90     100     111",
+		"Skew Angle= 15 ",
+		"Type RM Length L Ch R = 1 ,90     ,100,100,100",
+	}, "\n")
+	sc := bufio.NewScanner(strings.NewReader(text))
+	xs, i, skipScan, err := getXSData(sc, 0, strings.Split("1 ,100.5* ,50,60,70", ","))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := crossSections{
+		Name: "100.5*", Station: 100.5, Interpolated: true, Description: "Upstream face\nof the bridge",
+		LOBLength: 50, ChannelLength: 60, ROBLength: 70,
+		StationElevation: [][2]float64{{0, 110}, {50, 100}, {100, 110}},
+		ManningsN:        [][2]float64{{0, 0.06}, {40, 0.035}, {60, 0.06}},
+		LeftBank:         40, RightBank: 60, Contraction: 0.1, Expansion: 0.3, Skew: 15,
+		IneffectiveAreas: []xsBlocks{{StartStation: 0, EndStation: 30, Elevation: 108, Permanent: true}},
+		Obstructions:     []xsBlocks{{StartStation: 90, EndStation: 100, Elevation: 111, Permanent: true}},
+		LeftLevee:        &levees{Station: 30, Elevation: 112},
+		CutLine:          [][2]float64{{0, 0}, {100, 0}},
+		BankStations:     [2]string{"40", "60"},
+	}
+	if !reflect.DeepEqual(xs, expected) {
+		t.Errorf("got %+v, want %+v", xs, expected)
+	}
+	if !skipScan || i != 21 {
+		t.Errorf("got (%d, %v), want the next element at line 21 to be left to the caller", i, skipScan)
+	}
+	if !xs.hasBanks() {
+		t.Error("expected the cross section to have banks")
+	}
+
+	sc = bufio.NewScanner(strings.NewReader("#Sta/Elev= 2 \n       0     110     100     110"))
+	xs, _, skipScan, err = getXSData(sc, 0, strings.Split("1 ,200 ,0,0,0", ","))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipScan || xs.hasBanks() || xs.LeftBank != 0 || xs.RightBank != 0 {
+		t.Errorf("expected a cross section without banks at the end of the file, got %+v", xs)
+	}
+}
@@ -0,0 +1,270 @@
+package tools
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dewberry/gdal"
+	"github.com/go-errors/errors" // warning: replaces standard errors
+)
+
+// Exporter writes geospatial data to a file format
+type Exporter interface {
+	// Export writes the geospatial data to a file at path
+	Export(gd GeoData, path string) error
+	// Extension of the exported file
+	Extension() string
+	// ContentType of the exported file
+	ContentType() string
+}
+
+// Exporter writing through an OGR driver. Each non empty layer of each geometry file is written as its own layer
+// named after the geometry file and the layer.
+type ogrExporter struct {
+	driver       string
+	extension    string
+	contentType  string
+	zipped       bool // the driver writes one file per layer in a directory, which is zipped
+	maxFieldName int  // maximum length of attribute field names, 0 for no limit
+}
+
+// Exporters available by format name
+var Exporters map[string]Exporter = map[string]Exporter{
+	"gpkg":       ogrExporter{driver: "GPKG", extension: ".gpkg", contentType: "application/geopackage+sqlite3"},
+	"shapefile":  ogrExporter{driver: "ESRI Shapefile", extension: ".zip", contentType: "application/zip", zipped: true, maxFieldName: 10},
+	"flatgeobuf": ogrExporter{driver: "FlatGeobuf", extension: ".zip", contentType: "application/zip", zipped: true},
+	"kml":        ogrExporter{driver: "KML", extension: ".kml", contentType: "application/vnd.google-earth.kml+xml"},
+}
+
+// Extension of the exported file
+func (e ogrExporter) Extension() string {
+	return e.extension
+}
+
+// ContentType of the exported file
+func (e ogrExporter) ContentType() string {
+	return e.contentType
+}
+
+// Export writes the geospatial data to a file at path
+func (e ogrExporter) Export(gd GeoData, path string) error {
+	dsPath := path
+	if e.zipped {
+		dsPath = strings.TrimSuffix(path, filepath.Ext(path))
+	}
+
+	if err := e.write(gd, dsPath); err != nil {
+		return errors.Wrap(err, 0)
+	}
+
+	if e.zipped {
+		defer os.RemoveAll(dsPath)
+		if err := zipDir(dsPath, path); err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	return nil
+}
+
+func (e ogrExporter) write(gd GeoData, dsPath string) error {
+	driver := gdal.OGRDriverByName(e.driver)
+	ds, ok := driver.Create(dsPath, []string{})
+	if !ok {
+		return errors.Errorf("failed to create %s data source %s", e.driver, dsPath)
+	}
+	defer ds.Destroy()
+
+	spRef := gdal.CreateSpatialReference(gd.Georeference)
+
+	geomFileNames := make([]string, 0, len(gd.Features))
+	for geomFileName := range gd.Features {
+		geomFileNames = append(geomFileNames, geomFileName)
+	}
+	sort.Strings(geomFileNames)
+
+	for _, geomFileName := range geomFileNames {
+		for _, layer := range gd.Features[geomFileName].Layers() {
+			if len(layer.Features) == 0 {
+				continue
+			}
+			layerName := strings.ReplaceAll(fmt.Sprintf("%s_%s", geomFileName, layer.Name), ".", "_")
+			if err := writeOGRLayer(ds, layerName, spRef, layer.Features, e.maxFieldName); err != nil {
+				return errors.Wrap(err, 0)
+			}
+		}
+	}
+	return nil
+}
+
+// WriteGeoPackage writes the geospatial data to a GeoPackage at a local path
+func (gd GeoData) WriteGeoPackage(path string) error {
+	return Exporters["gpkg"].Export(gd, path)
+}
+
+// Zip the files of a directory
+func zipDir(dir string, zipPath string) error {
+	zipFile, err := os.Create(zipPath)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	defer zipFile.Close()
+
+	zw := zip.NewWriter(zipFile)
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		w, err := zw.Create(file.Name())
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		body, err := ioutil.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		if _, err := w.Write(body); err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return errors.Wrap(err, 0)
+	}
+	return nil
+}
+
+// Write features to a new layer of an OGR data source. The attribute fields are the union of the fields of all features.
+// Field names longer than maxFieldName are truncated, keeping them unique.
+func writeOGRLayer(ds gdal.DataSource, name string, spRef gdal.SpatialReference, features []VectorFeature, maxFieldName int) error {
+	layer := ds.CreateLayer(name, spRef, gdal.GT_Unknown, []string{})
+
+	fieldNames, fieldTypes := layerFields(features)
+	ogrNames := truncateFieldNames(fieldNames, maxFieldName)
+	for _, fieldName := range fieldNames {
+		fieldDef := gdal.CreateFieldDefinition(ogrNames[fieldName], fieldTypes[fieldName])
+		err := layer.CreateField(fieldDef, true)
+		fieldDef.Destroy()
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+
+	layerDef := layer.Definition()
+	for _, vf := range features {
+		feature := layerDef.Create()
+		feature.SetFieldString(layerDef.FieldIndex(ogrNames["feature_name"]), vf.FeatureName)
+		for key, value := range vf.Fields {
+			setOGRField(feature, layerDef.FieldIndex(ogrNames[key]), value)
+		}
+
+		if len(vf.Geometry) > 0 {
+			geom, err := gdal.CreateFromWKB(vf.Geometry, spRef, len(vf.Geometry))
+			if err != nil {
+				feature.Destroy()
+				return errors.Wrap(err, 0)
+			}
+			if err := feature.SetGeometryDirectly(geom); err != nil {
+				feature.Destroy()
+				return errors.Wrap(err, 0)
+			}
+		}
+
+		err := layer.Create(feature)
+		feature.Destroy()
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	return nil
+}
+
+// Sorted names and OGR types of the attribute fields of a list of features, starting with the feature name.
+// The type of a field is taken from its first non nil value, values other than numbers, booleans and strings are written as JSON.
+func layerFields(features []VectorFeature) ([]string, map[string]gdal.FieldType) {
+	fieldTypes := map[string]gdal.FieldType{"feature_name": gdal.FT_String}
+	for _, vf := range features {
+		for key, value := range vf.Fields {
+			if _, ok := fieldTypes[key]; ok || value == nil {
+				continue
+			}
+			switch value.(type) {
+			case float64, float32:
+				fieldTypes[key] = gdal.FT_Real
+			case int, int64, bool:
+				fieldTypes[key] = gdal.FT_Integer64
+			default:
+				fieldTypes[key] = gdal.FT_String
+			}
+		}
+	}
+
+	fieldNames := make([]string, 0, len(fieldTypes))
+	for key := range fieldTypes {
+		if key != "feature_name" {
+			fieldNames = append(fieldNames, key)
+		}
+	}
+	sort.Strings(fieldNames)
+	return append([]string{"feature_name"}, fieldNames...), fieldTypes
+}
+
+// Map field names to names of at most maxLength characters, suffixing a number to truncated names which collide
+func truncateFieldNames(fieldNames []string, maxLength int) map[string]string {
+	ogrNames := make(map[string]string, len(fieldNames))
+	used := make(map[string]bool, len(fieldNames))
+	for _, fieldName := range fieldNames {
+		ogrName := fieldName
+		if maxLength > 0 && len(ogrName) > maxLength {
+			ogrName = fieldName[:maxLength]
+		}
+		for n := 1; used[ogrName]; n++ {
+			suffix := strconv.Itoa(n)
+			base := fieldName
+			if maxLength > 0 && len(base)+len(suffix) > maxLength {
+				base = base[:maxLength-len(suffix)]
+			}
+			ogrName = base + suffix
+		}
+		used[ogrName] = true
+		ogrNames[fieldName] = ogrName
+	}
+	return ogrNames
+}
+
+func setOGRField(feature gdal.Feature, idx int, value interface{}) {
+	if idx < 0 || value == nil {
+		return
+	}
+	switch v := value.(type) {
+	case float64:
+		feature.SetFieldFloat64(idx, v)
+	case float32:
+		feature.SetFieldFloat64(idx, float64(v))
+	case int:
+		feature.SetFieldInteger64(idx, int64(v))
+	case int64:
+		feature.SetFieldInteger64(idx, v)
+	case bool:
+		if v {
+			feature.SetFieldInteger64(idx, 1)
+		} else {
+			feature.SetFieldInteger64(idx, 0)
+		}
+	case string:
+		feature.SetFieldString(idx, v)
+	default:
+		if data, err := json.Marshal(v); err == nil {
+			feature.SetFieldString(idx, string(data))
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTruncateFieldNames(t *testing.T) {
+	tests := []struct {
+		name       string
+		fieldNames []string
+		maxLength  int
+		expected   map[string]string
+	}{
+		{
+			"no limit", []string{"feature_name", "RiverReachName"}, 0,
+			map[string]string{"feature_name": "feature_name", "RiverReachName": "RiverReachName"},
+		},
+		{
+			"short names", []string{"RS", "Type"}, 10,
+			map[string]string{"RS": "RS", "Type": "Type"},
+		},
+		{
+			"truncated collision", []string{"RiverReachName", "RiverReachLength"}, 10,
+			map[string]string{"RiverReachName": "RiverReach", "RiverReachLength": "RiverReac1"},
+		},
+		{
+			"several collisions", []string{"ManningsN_LOB", "ManningsN_Chan", "ManningsN_ROB"}, 10,
+			map[string]string{"ManningsN_LOB": "ManningsN_", "ManningsN_Chan": "ManningsN1", "ManningsN_ROB": "ManningsN2"},
+		},
+		{
+			"collision with a name at the limit", []string{"Station123", "Station1234"}, 10,
+			map[string]string{"Station123": "Station123", "Station1234": "Station121"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if ogrNames := truncateFieldNames(tc.fieldNames, tc.maxLength); !reflect.DeepEqual(ogrNames, tc.expected) {
+				t.Errorf("got %v, want %v", ogrNames, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/dewberry/gdal"
+	"github.com/go-errors/errors" // warning: replaces standard errors
+)
+
+// GeoJSONData holds the geospatial data of a model as one GeoJSON FeatureCollection per layer of each geometry file
+type GeoJSONData struct {
+	Features           map[string]map[string]FeatureCollection
+	Georeference       string
+	GeoreferenceSource string
+	VertexCounts       map[string]map[string]VertexCount `json:",omitempty"`
+}
+
+// FeatureCollection is a GeoJSON FeatureCollection
+type FeatureCollection struct {
+	Type     string           `json:"type"`
+	Features []GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature is a GeoJSON Feature, the fields of the vector feature are its properties
+type GeoJSONFeature struct {
+	Type       string                 `json:"type"`
+	Properties map[string]interface{} `json:"properties"`
+	Geometry   json.RawMessage        `json:"geometry"`
+}
+
+// ToGeoJSON converts the WKB geometries of the geospatial data to GeoJSON
+func (gd GeoData) ToGeoJSON() (GeoJSONData, error) {
+	data := GeoJSONData{Features: make(map[string]map[string]FeatureCollection), Georeference: gd.Georeference, GeoreferenceSource: gd.GeoreferenceSource, VertexCounts: gd.VertexCounts}
+	for geomFileName, f := range gd.Features {
+		collections := make(map[string]FeatureCollection)
+		for _, layer := range f.Layers() {
+			collection, err := toFeatureCollection(layer.Features)
+			if err != nil {
+				return data, errors.Wrap(err, 0)
+			}
+			collections[layer.Name] = collection
+		}
+		data.Features[geomFileName] = collections
+	}
+	return data, nil
+}
+
+func toFeatureCollection(features []VectorFeature) (FeatureCollection, error) {
+	collection := FeatureCollection{Type: "FeatureCollection", Features: []GeoJSONFeature{}}
+	for _, feature := range features {
+		geoJSONFeature, err := toGeoJSONFeature(feature)
+		if err != nil {
+			return collection, errors.Wrap(err, 0)
+		}
+		collection.Features = append(collection.Features, geoJSONFeature)
+	}
+	return collection, nil
+}
+
+func toGeoJSONFeature(feature VectorFeature) (GeoJSONFeature, error) {
+	properties := map[string]interface{}{"feature_name": feature.FeatureName}
+	for key, value := range feature.Fields {
+		properties[key] = value
+	}
+	geoJSONFeature := GeoJSONFeature{Type: "Feature", Properties: properties, Geometry: json.RawMessage("null")}
+
+	if len(feature.Geometry) == 0 {
+		return geoJSONFeature, nil
+	}
+	geom, err := gdal.CreateFromWKB(feature.Geometry, gdal.SpatialReference{}, len(feature.Geometry))
+	if err != nil {
+		return geoJSONFeature, errors.Wrap(err, 0)
+	}
+	defer geom.Destroy()
+
+	geoJSONFeature.Geometry = json.RawMessage(geom.ToJSON())
+	return geoJSONFeature, nil
+}
+
+// NDJSONSink returns a feature sink writing each feature as a GeoJSON Feature on its own line, with the geometry file
+// and the layer as properties. flush is called after each line.
+func NDJSONSink(w io.Writer, flush func()) FeatureSink {
+	encoder := json.NewEncoder(w)
+	return func(geomFileName string, layer string, feature VectorFeature) error {
+		geoJSONFeature, err := toGeoJSONFeature(feature)
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+		geoJSONFeature.Properties["geom_file"] = geomFileName
+		geoJSONFeature.Properties["layer"] = layer
+
+		if err := encoder.Encode(geoJSONFeature); err != nil {
+			return errors.Wrap(err, 0)
+		}
+		flush()
+		return nil
+	}
+}
@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dewberry/gdal"
+)
+
+func TestFeaturesLayers(t *testing.T) {
+	names := func(layers []Layer) []string {
+		layerNames := []string{}
+		for _, layer := range layers {
+			layerNames = append(layerNames, layer.Name)
+		}
+		return layerNames
+	}
+	expected := []string{
+		"Rivers", "XS", "Banks", "BankLines", "FlowPaths", "Levees", "StorageAreas", "TwoDAreas",
+		"HydraulicStructures", "Connections", "BCLines", "BreakLines", "RefinementRegions", "Junctions", "PumpStations",
+	}
+
+	if got := names(Features{}.Layers()); !reflect.DeepEqual(got, expected) {
+		t.Errorf("got layers %v, want %v", got, expected)
+	}
+
+	f := Features{XS: []VectorFeature{{FeatureName: "100"}}, MeshPoints: []VectorFeature{{FeatureName: "Area 1"}}}
+	layers := f.Layers()
+	if got := names(layers); !reflect.DeepEqual(got, append(expected, "MeshPoints")) {
+		t.Errorf("got layers %v, want mesh points last", got)
+	}
+	if !reflect.DeepEqual(layers[1].Features, f.XS) {
+		t.Errorf("got XS features %+v, want %+v", layers[1].Features, f.XS)
+	}
+}
+
+func TestToGeoJSON(t *testing.T) {
+	lineString := gdal.Create(gdal.GT_LineString)
+	lineString.AddPoint2D(1, 2)
+	lineString.AddPoint2D(3, 4)
+	wkb, err := lineString.ForceToMultiLineString().ToWKB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gd := GeoData{
+		Features: map[string]Features{
+			"model.g01": {
+				Rivers: []VectorFeature{{FeatureName: "Creek, Upper", Fields: map[string]interface{}{"Length": 2.5}, Geometry: wkb}},
+				XS:     []VectorFeature{{FeatureName: "100"}},
+			},
+		},
+		Georeference: "WKT",
+	}
+	data, err := gd.ToGeoJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data.Georeference != "WKT" || len(data.Features["model.g01"]) != len(Features{}.Layers()) {
+		t.Fatalf("got %+v, want one collection per layer", data)
+	}
+
+	collection := data.Features["model.g01"]["Rivers"]
+	if collection.Type != "FeatureCollection" || len(collection.Features) != 1 {
+		t.Fatalf("got rivers %+v, want a collection of one feature", collection)
+	}
+	feature := collection.Features[0]
+	expectedProperties := map[string]interface{}{"feature_name": "Creek, Upper", "Length": 2.5}
+	if feature.Type != "Feature" || !reflect.DeepEqual(feature.Properties, expectedProperties) {
+		t.Errorf("got feature %+v, want properties %v", feature, expectedProperties)
+	}
+
+	geometry := struct {
+		Type        string
+		Coordinates [][][]float64
+	}{}
+	if err := json.Unmarshal(feature.Geometry, &geometry); err != nil {
+		t.Fatal(err)
+	}
+	if geometry.Type != "MultiLineString" || !reflect.DeepEqual(geometry.Coordinates, [][][]float64{{{1, 2}, {3, 4}}}) {
+		t.Errorf("got geometry %s, want the river line", feature.Geometry)
+	}
+
+	if xs := data.Features["model.g01"]["XS"].Features[0]; string(xs.Geometry) != "null" {
+		t.Errorf("got geometry %s for a feature without geometry, want null", xs.Geometry)
+	}
+	if banks := data.Features["model.g01"]["Banks"]; banks.Features == nil || len(banks.Features) != 0 {
+		t.Errorf("got banks %+v, want an empty collection", banks)
+	}
+}
+
+func TestNDJSONSink(t *testing.T) {
+	point := gdal.Create(gdal.GT_Point)
+	point.AddPoint2D(1, 2)
+	wkb, err := point.ForceToMultiPoint().ToWKB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := &bytes.Buffer{}
+	flushes := 0
+	sink := NDJSONSink(buf, func() { flushes++ })
+	features := []VectorFeature{
+		{FeatureName: "Junction 1", Fields: map[string]interface{}{}, Geometry: wkb},
+		{FeatureName: "Pump 1", Fields: map[string]interface{}{"Groups": 2.0}},
+	}
+	for _, feature := range features {
+		if err := sink("model.g01", "Junctions", feature); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if flushes != len(features) {
+		t.Errorf("got %d flushes, want one per feature", flushes)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(features) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(features), buf.String())
+	}
+	expected := []map[string]interface{}{
+		{"feature_name": "Junction 1", "geom_file": "model.g01", "layer": "Junctions"},
+		{"feature_name": "Pump 1", "geom_file": "model.g01", "layer": "Junctions", "Groups": 2.0},
+	}
+	for n, line := range lines {
+		feature := GeoJSONFeature{}
+		if err := json.Unmarshal([]byte(line), &feature); err != nil {
+			t.Fatalf("line %d is not a GeoJSON Feature: %v", n, err)
+		}
+		if feature.Type != "Feature" || !reflect.DeepEqual(feature.Properties, expected[n]) {
+			t.Errorf("line %d: got %+v, want properties %v", n, feature, expected[n])
+		}
+	}
+	if !strings.Contains(lines[0], `"type":"MultiPoint"`) || !strings.HasSuffix(lines[1], `"geometry":null}`) {
+		t.Errorf("got geometries %s and %s", lines[0], lines[1])
+	}
+}
@@ -11,18 +11,37 @@ import (
 	"sync"
 )
 
+// These prefixes are used to determine the beginning of HEC-RAS geometry elements
+var geomElementsPrefix = [...]string{
+	"Type RM Length L Ch R",
+	"River Reach",
+	"Junct Name",
+	"Storage Area",
+	"Connection",
+	"BreakLine Name",
+	"BC Line Name",
+	"LCMann Time",
+	"Pump Station",
+	"Refinement Region Name",
+}
+
 // GeomFileContents keywords and data container for ras flow file search
 type GeomFileContents struct {
 	Path           string
 	Hash           string
-	FileExt        string                 `json:"File Extension"`
-	GeomTitle      string                 `json:"Geom Title"`
-	ProgramVersion string                 `json:"Program Version"`
-	Description    string                 `json:"Description"`
-	Structures     []hydraulicStructures  `json:"Hydraulic Structures"`
-	StorageAreas   map[string]StorageArea `json:"Storage Areas"`
-	TwoDAreas      map[string]TwoDArea    `json:"2D Areas"`
-	Connections    map[string]Connection  `json:"Connections"`
+	FileExt        string                      `json:"File Extension"`
+	GeomTitle      string                      `json:"Geom Title"`
+	ProgramVersion string                      `json:"Program Version"`
+	Description    string                      `json:"Description"`
+	Structures     []hydraulicStructures       `json:"Hydraulic Structures"`
+	StorageAreas   map[string]StorageArea      `json:"Storage Areas"`
+	TwoDAreas      map[string]TwoDArea         `json:"2D Areas"`
+	Connections    map[string]Connection       `json:"Connections"`
+	Junctions      map[string]Junction         `json:"Junctions"`
+	ReachNetwork   ReachNetwork                `json:"Reach Network"`
+	PumpStations   map[string]PumpStation      `json:"Pump Stations"`
+	BreakLines     map[string]BreakLine        `json:"Breaklines"`
+	Refinements    map[string]RefinementRegion `json:"Refinement Regions"`
 	Notes          string
 }
 
@@ -37,6 +56,10 @@ func getGeomData(rm *RasModel, fn string, wg *sync.WaitGroup) {
 		StorageAreas: make(map[string]StorageArea),
 		TwoDAreas:    make(map[string]TwoDArea),
 		Connections:  make(map[string]Connection),
+		Junctions:    make(map[string]Junction),
+		PumpStations: make(map[string]PumpStation),
+		BreakLines:   make(map[string]BreakLine),
+		Refinements:  make(map[string]RefinementRegion),
 	}
 
 	var err error
@@ -119,6 +142,42 @@ func getGeomData(rm *RasModel, fn string, wg *sync.WaitGroup) {
 			meta.Connections[connName] = connecData
 			header = false
 
+		case strings.HasPrefix(line, "Junct Name="):
+			junctName, junction, err := getJunctionsData(rm, fn, idx)
+			if err != nil {
+				log.Println("Junctions|", meta.FileExt, err)
+				continue
+			}
+			meta.Junctions[junctName] = junction
+			header = false
+
+		case strings.HasPrefix(line, "Pump Station="):
+			pumpName, pump, err := getPumpStationsData(rm, fn, idx)
+			if err != nil {
+				log.Println("Pump Stations|", meta.FileExt, err)
+				continue
+			}
+			meta.PumpStations[pumpName] = pump
+			header = false
+
+		case strings.HasPrefix(line, "BreakLine Name="):
+			blName, breakLine, err := getBreakLinesData(rm, fn, idx)
+			if err != nil {
+				log.Println("Breaklines|", meta.FileExt, err)
+				continue
+			}
+			meta.BreakLines[blName] = breakLine
+			header = false
+
+		case strings.HasPrefix(line, "Refinement Region Name="):
+			regionName, region, err := getRefinementRegionsData(rm, fn, idx)
+			if err != nil {
+				log.Println("Refinement Regions|", meta.FileExt, err)
+				continue
+			}
+			meta.Refinements[regionName] = region
+			header = false
+
 		case strings.HasPrefix(line, "BC Line Name="):
 			bcArea, bc, err := getBCLineData(rm, fn, idx)
 			if err != nil {
@@ -138,6 +197,34 @@ func getGeomData(rm *RasModel, fn string, wg *sync.WaitGroup) {
 
 		}
 	}
+
+	reaches := make([]string, 0, len(meta.Structures))
+	for _, structures := range meta.Structures {
+		reaches = append(reaches, fmt.Sprintf("%s, %s", structures.River, structures.Reach))
+	}
+	meta.ReachNetwork = buildReachNetwork(reaches, meta.Junctions)
+
+	// lateral structures only name the area they connect to, classify it as a storage area or a 2D area
+	for _, structures := range meta.Structures {
+		for l, lateral := range structures.LateralData.Laterals {
+			if _, ok := meta.TwoDAreas[lateral.ConnectedSA]; ok {
+				structures.LateralData.Laterals[l].Connected2D = lateral.ConnectedSA
+				structures.LateralData.Laterals[l].ConnectedSA = ""
+			}
+		}
+	}
+
+	// pump stations only name the areas they connect, classify them as storage areas or 2D areas
+	for name, pump := range meta.PumpStations {
+		if _, ok := meta.TwoDAreas[pump.From.StorageArea]; ok {
+			pump.From.TwoDArea, pump.From.StorageArea = pump.From.StorageArea, ""
+		}
+		if _, ok := meta.TwoDAreas[pump.To.StorageArea]; ok {
+			pump.To.TwoDArea, pump.To.StorageArea = pump.To.StorageArea, ""
+		}
+		meta.PumpStations[name] = pump
+	}
+
 	msg = ""
 	meta.Hash = fmt.Sprintf("%x", hasher.Sum(nil))
 
@@ -7,6 +7,7 @@ import (
 	"math"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/USACE/filestore"
@@ -14,10 +15,85 @@ import (
 	"github.com/go-errors/errors" // warning: replaces standard errors
 )
 
+// GeoOptions controls which layers of the geospatial data are extracted and how
+type GeoOptions struct {
+	MeshPoints         bool        // 2D area cell centers, one multipoint feature per area
+	Reconcile          string      // method to fit cross section profiles to cut lines of a different length, stretch by default
+	ReconcileTolerance float64     // length difference below which no reconciliation is needed, 0.1 by default
+	Layers             []string    // names of the layers to extract, all layers when empty
+	GeomFiles          []string    // extensions of the geometry files to extract, e.g. g01, all geometry files when empty
+	BBox               *[4]float64 // minimum x, minimum y, maximum x and maximum y in the destination CRS, features outside are dropped
+	Simplify           float64     // topology preserving simplification tolerance in destination CRS units, no simplification when 0
+	Precision          *int        // number of decimals of the x and y coordinates, full precision when nil
+	Sink               FeatureSink // receives the features as they are extracted instead of keeping them in the GeoData
+	SourceCRS          string      // EPSG code, WKT or PROJ string of the model coordinates, overrides the model projection
+}
+
+// FeatureSink receives a feature of a layer of a geometry file
+type FeatureSink func(geomFileName string, layer string, feature VectorFeature) error
+
+// Check if a layer must be extracted. Mesh points are extracted when asked for either by option or by layer name.
+func (opts GeoOptions) wantLayer(name string) bool {
+	if name == "MeshPoints" {
+		return opts.MeshPoints || stringInSlice(name, opts.Layers)
+	}
+	return len(opts.Layers) == 0 || stringInSlice(name, opts.Layers)
+}
+
+// Check if a geometry file must be extracted
+func (opts GeoOptions) wantGeomFile(geomFilePath string) bool {
+	if len(opts.GeomFiles) == 0 {
+		return true
+	}
+	ext := strings.TrimPrefix(filepath.Ext(geomFilePath), ".")
+	for _, geomFile := range opts.GeomFiles {
+		if strings.EqualFold(strings.TrimPrefix(geomFile, "."), ext) {
+			return true
+		}
+	}
+	return false
+}
+
 // GeoData ...
 type GeoData struct {
-	Features     map[string]Features
-	Georeference int
+	Features           map[string]Features
+	Georeference       string                            // WKT of the CRS of the features
+	GeoreferenceSource string                            // model or user-georeferenced
+	VertexCounts       map[string]map[string]VertexCount `json:",omitempty"` // per geometry file and layer, when simplified
+}
+
+// Sources of the coordinate reference system of the model coordinates
+const (
+	GeoreferenceModel = "model"              // projection of the model
+	GeoreferenceUser  = "user-georeferenced" // CRS supplied by the caller
+)
+
+// NewGeoData creates empty geospatial data georeferenced with the CRS of the options, or the model projection when
+// the options have none, after checking that the CRS and the model use the same units.
+// Returns the WKT of the CRS of the model coordinates.
+func NewGeoData(projection string, modelUnits string, destinationCRS string, opts GeoOptions) (GeoData, string, error) {
+	gd := GeoData{Features: make(map[string]Features), GeoreferenceSource: GeoreferenceModel}
+
+	sourceCRS := projection
+	if opts.SourceCRS != "" {
+		var err error
+		sourceCRS, err = CRSWKT(opts.SourceCRS)
+		if err != nil {
+			return gd, sourceCRS, errors.Wrap(err, 0)
+		}
+		gd.GeoreferenceSource = GeoreferenceUser
+	}
+
+	if err := checkUnitConsistency(modelUnits, sourceCRS); err != nil {
+		return gd, sourceCRS, errors.Wrap(err, 0)
+	}
+
+	georeference, err := GeoreferenceWKT(sourceCRS, destinationCRS)
+	if err != nil {
+		return gd, sourceCRS, errors.Wrap(err, 0)
+	}
+	gd.Georeference = georeference
+	return gd, sourceCRS, nil
 }
 
 // Features ...
@@ -25,12 +101,99 @@ type Features struct {
 	Rivers              []VectorFeature
 	XS                  []VectorFeature
 	Banks               []VectorFeature
+	BankLines           []VectorFeature
+	FlowPaths           []VectorFeature
+	Levees              []VectorFeature
 	StorageAreas        []VectorFeature
 	TwoDAreas           []VectorFeature
 	HydraulicStructures []VectorFeature
 	Connections         []VectorFeature
 	BCLines             []VectorFeature
 	BreakLines          []VectorFeature
+	RefinementRegions   []VectorFeature
+	Junctions           []VectorFeature
+	PumpStations        []VectorFeature
+	MeshPoints          []VectorFeature `json:",omitempty"`
+}
+
+// Layer is a named list of features of a geometry file
+type Layer struct {
+	Name     string
+	Features []VectorFeature
+}
+
+// Layers of the features in a fixed order, referenced so that they can be modified
+func (f *Features) layerRefs() []layerRef {
+	return []layerRef{
+		{"Rivers", &f.Rivers},
+		{"XS", &f.XS},
+		{"Banks", &f.Banks},
+		{"BankLines", &f.BankLines},
+		{"FlowPaths", &f.FlowPaths},
+		{"Levees", &f.Levees},
+		{"StorageAreas", &f.StorageAreas},
+		{"TwoDAreas", &f.TwoDAreas},
+		{"HydraulicStructures", &f.HydraulicStructures},
+		{"Connections", &f.Connections},
+		{"BCLines", &f.BCLines},
+		{"BreakLines", &f.BreakLines},
+		{"RefinementRegions", &f.RefinementRegions},
+		{"Junctions", &f.Junctions},
+		{"PumpStations", &f.PumpStations},
+		{"MeshPoints", &f.MeshPoints},
+	}
+}
+
+type layerRef struct {
+	name     string
+	features *[]VectorFeature
+}
+
+// Append a feature to a layer
+func (f *Features) add(layer string, feature VectorFeature) {
+	for _, ref := range f.layerRefs() {
+		if ref.name == layer {
+			*ref.features = append(*ref.features, feature)
+			return
+		}
+	}
+}
+
+// Layers returns the layers of the features in a fixed order. Mesh points are only included when extracted.
+func (f Features) Layers() []Layer {
+	layers := []Layer{}
+	for _, ref := range f.layerRefs() {
+		if ref.name == "MeshPoints" && len(*ref.features) == 0 {
+			continue
+		}
+		layers = append(layers, Layer{ref.name, *ref.features})
+	}
+	return layers
+}
+
+// ValidLayer checks that a layer name is one of the layers of the features
+func ValidLayer(name string) bool {
+	for _, ref := range (&Features{}).layerRefs() {
+		if ref.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if the envelope of a WKB geometry intersects a bounding box of minimum x, minimum y, maximum x and maximum y
+func intersectsBBox(wkb []uint8, bbox [4]float64) (bool, error) {
+	if len(wkb) == 0 {
+		return false, nil
+	}
+	geom, err := gdal.CreateFromWKB(wkb, gdal.SpatialReference{}, len(wkb))
+	if err != nil {
+		return false, errors.Wrap(err, 0)
+	}
+	defer geom.Destroy()
+
+	env := geom.Envelope()
+	return env.MinX() <= bbox[2] && env.MaxX() >= bbox[0] && env.MinY() <= bbox[3] && env.MaxY() >= bbox[1], nil
 }
 
 // VectorFeature ...
@@ -126,62 +289,6 @@ func attributeZ(xyPairs [][2]float64, mzPairs [][2]float64) []xyzPoint {
 	return points
 }
 
-func getTransform(sourceCRS string, destinationCRS int) (gdal.CoordinateTransform, error) {
-	transform := gdal.CoordinateTransform{}
-	sourceSpRef := gdal.CreateSpatialReference(sourceCRS)
-
-	destinationSpRef := gdal.CreateSpatialReference("")
-	if err := destinationSpRef.FromEPSG(destinationCRS); err != nil {
-		return transform, errors.Wrap(err, 0)
-	}
-	transform = gdal.CreateCoordinateTransform(sourceSpRef, destinationSpRef)
-	return transform, nil
-}
-
-func flipXYLineString(xyLineString gdal.Geometry) gdal.Geometry {
-	yxLineString := gdal.Create(gdal.GT_LineString)
-	nPoints := xyLineString.PointCount()
-	for i := 0; i < nPoints; i++ {
-		x, y, _ := xyLineString.Point(i)
-		yxLineString.AddPoint2D(y, x)
-	}
-	xyLineString.Destroy()
-	return yxLineString
-}
-
-func flipXYLineString25D(xyzLineString gdal.Geometry) gdal.Geometry {
-	yxzLineString := gdal.Create(gdal.GT_LineString25D)
-	nPoints := xyzLineString.PointCount()
-	for i := 0; i < nPoints; i++ {
-		x, y, z := xyzLineString.Point(i)
-		yxzLineString.AddPoint(y, x, z)
-	}
-	xyzLineString.Destroy()
-	return yxzLineString
-}
-
-func flipXYLinearRing(xyLinearRing gdal.Geometry) gdal.Geometry {
-	yxLinearRing := gdal.Create(gdal.GT_LinearRing)
-	nPoints := xyLinearRing.PointCount()
-	for i := 0; i < nPoints; i++ {
-		x, y, _ := xyLinearRing.Point(i)
-		yxLinearRing.AddPoint2D(y, x)
-	}
-	xyLinearRing.Destroy()
-	return yxLinearRing
-}
-
-func flipXYPoint(xyPoint gdal.Geometry) gdal.Geometry {
-	yxPoint := gdal.Create(gdal.GT_Point)
-	nPoints := xyPoint.PointCount()
-	for i := 0; i < nPoints; i++ {
-		x, y, _ := xyPoint.Point(i)
-		yxPoint.AddPoint2D(y, x)
-	}
-	xyPoint.Destroy()
-	return yxPoint
-}
-
 func toNumeric(s string) (string, error) {
 	reg, err := regexp.Compile("[^.0-9]+")
 	if err != nil {
@@ -191,13 +298,42 @@ func toNumeric(s string) (string, error) {
 	return num, nil
 }
 
-func getRiverCenterline(sc *bufio.Scanner, transform gdal.CoordinateTransform) (VectorFeature, error) {
+// Extract the centerline coordinates of a reach from a River Reach text block.
+// Older geometry files may not have any, in which case the next geometry element is encountered and the next scan must be skipped.
+func getReachXY(sc *bufio.Scanner) ([][2]float64, bool, error) {
+	for sc.Scan() {
+		line := sc.Text()
+		if stringInSlice(leftofEquals(line), geomElementsPrefix[:]) {
+			// a new HEC RAS element has been encountered, skip next scan and return
+			return [][2]float64{}, true, nil
+		}
+		if strings.HasPrefix(line, "Reach XY=") {
+			nPairs, err := strconv.Atoi(rightofEquals(line))
+			if err != nil {
+				return [][2]float64{}, false, errors.Wrap(err, 0)
+			}
+			pairs, err := dataPairsfromTextBlock(sc, nPairs, 64, 16)
+			if err != nil {
+				return pairs, false, errors.Wrap(err, 0)
+			}
+			return pairs, false, nil
+		}
+	}
+	return [][2]float64{}, false, nil
+}
+
+// Returns when a new geometry element is encountered, in which case the next scan must be skipped.
+// A reach without centerline coordinates returns an Invalid Line Geometry error.
+func getRiverCenterline(sc *bufio.Scanner, transform gisTransform) (VectorFeature, bool, error) {
 	riverReach := strings.Split(rightofEquals(sc.Text()), ",")
 	feature := VectorFeature{FeatureName: fmt.Sprintf("%s, %s", strings.TrimSpace(riverReach[0]), strings.TrimSpace(riverReach[1]))}
 
-	xyPairs, err := getDataPairsfromTextBlock("Reach XY=", sc, 64, 16)
+	xyPairs, skipScan, err := getReachXY(sc)
 	if err != nil {
-		return feature, errors.Wrap(err, 0)
+		return feature, skipScan, errors.Wrap(err, 0)
+	}
+	if len(xyPairs) < 2 {
+		return feature, skipScan, errors.New("Invalid Line Geometry")
 	}
 
 	xyLineString := gdal.Create(gdal.GT_LineString)
@@ -205,124 +341,156 @@ func getRiverCenterline(sc *bufio.Scanner, transform gdal.CoordinateTransform) (
 		xyLineString.AddPoint2D(pair[0], pair[1])
 	}
 
-	xyLineString.Transform(transform)
-	// This is a temporary fix since the x and y values need to be flipped:
-	yxLineString := flipXYLineString(xyLineString)
+	transform.apply(xyLineString)
 
-	multiLineString := yxLineString.ForceToMultiLineString()
+	multiLineString := xyLineString.ForceToMultiLineString()
 
 	wkb, err := multiLineString.ToWKB()
 	if err != nil {
-		return feature, errors.Wrap(err, 0)
+		return feature, skipScan, errors.Wrap(err, 0)
 	}
 	feature.Geometry = wkb
-	return feature, nil
+	return feature, skipScan, nil
 }
 
-func getXSBanks(sc *bufio.Scanner, transform gdal.CoordinateTransform, riverReachName string) (VectorFeature, []VectorFeature, error) {
+func getXSBanks(sc *bufio.Scanner, transform gisTransform, riverReachName string, opts GeoOptions) (VectorFeature, []VectorFeature, []VectorFeature, bool, error) {
 	bankLayer := []VectorFeature{}
+	leveeLayer := []VectorFeature{}
 
-	xsFeature, xyPairs, startingStation, err := getXS(sc, transform, riverReachName)
+	lineData := strings.Split(rightofEquals(sc.Text()), ",")
+	xs, _, skipScan, err := getXSData(sc, 0, lineData)
 	if err != nil {
-		return xsFeature, bankLayer, errors.Wrap(err, 0)
+		return VectorFeature{}, bankLayer, leveeLayer, skipScan, errors.Wrap(err, 0)
 	}
 
-	if xsFeature.Fields["CutLineProfileMatch"].(bool) {
-		for sc.Scan() {
-			line := sc.Text()
-			if strings.HasPrefix(line, "Bank Sta=") {
-				bankLayer, err = getBanks(line, transform, xsFeature, xyPairs, startingStation)
-				if err != nil {
-					return xsFeature, bankLayer, errors.Wrap(err, 0)
-				}
-				break
-			}
+	xsFeature, fittedXS, rec, err := getXS(xs, transform, riverReachName, opts)
+	if err != nil {
+		return xsFeature, bankLayer, leveeLayer, skipScan, errors.Wrap(err, 0)
+	}
+
+	if rec.hasProfile() {
+		bankLayer, err = getBanks(fittedXS, transform, xsFeature)
+		if err != nil {
+			return xsFeature, bankLayer, leveeLayer, skipScan, errors.Wrap(err, 0)
+		}
+
+		leveeLayer, err = getLeveePoints(fittedXS, transform, xsFeature)
+		if err != nil {
+			return xsFeature, bankLayer, leveeLayer, skipScan, errors.Wrap(err, 0)
 		}
 	}
 
-	return xsFeature, bankLayer, nil
+	return xsFeature, bankLayer, leveeLayer, skipScan, nil
 }
 
-func getXS(sc *bufio.Scanner, transform gdal.CoordinateTransform, riverReachName string) (VectorFeature, [][2]float64, float64, error) {
-	xyPairs := [][2]float64{}
+const noCutLineError = "the cross-section cutline could not be extracted, check that the geometry file contains cutlines"
+
+// Create the cross section feature. The profile is fitted to the cut line before elevations are assigned,
+// the fitted cross section is returned so that banks and levees can be placed consistently.
+func getXS(xs crossSections, transform gisTransform, riverReachName string, opts GeoOptions) (VectorFeature, crossSections, xsReconciliation, error) {
 	feature := VectorFeature{Fields: map[string]interface{}{}}
 	feature.Fields["RiverReachName"] = riverReachName
-	feature.Fields["CutLineProfileMatch"] = false
-
-	compData := strings.Split(rightofEquals(sc.Text()), ",")
-
-	xsName, err := toNumeric(compData[1])
+	feature.Fields["Interpolated"] = xs.Interpolated
+	feature.Fields["Description"] = xs.Description
+	feature.Fields["LOBLength"] = xs.LOBLength
+	feature.Fields["ChannelLength"] = xs.ChannelLength
+	feature.Fields["ROBLength"] = xs.ROBLength
+	feature.Fields["LeftBank"] = xs.LeftBank
+	feature.Fields["RightBank"] = xs.RightBank
+	feature.Fields["Contraction"] = xs.Contraction
+	feature.Fields["Expansion"] = xs.Expansion
+	feature.Fields["Skew"] = xs.Skew
+
+	xs, rec := reconcileXS(xs, opts)
+	feature.Fields["CutLineProfileMatch"] = rec.Match
+	feature.Fields["ReconcileMethod"] = rec.Method
+	feature.Fields["MismatchRatio"] = rec.MismatchRatio
+
+	xsName, err := toNumeric(xs.Name)
 	if err != nil {
-		return feature, xyPairs, 0.0, errors.Wrap(err, 0)
+		return feature, xs, rec, errors.Wrap(err, 0)
 	}
 	feature.FeatureName = xsName
 
-	xyPairs, err = getDataPairsfromTextBlock("XS GIS Cut Line", sc, 64, 16)
-	if err != nil {
-		return feature, xyPairs, 0.0, errors.Wrap(err, 0)
-	}
-
+	xyPairs := xs.CutLine
 	if len(xyPairs) < 2 {
-		err = errors.New("the cross-section cutline could not be extracted, check that the geometry file contains cutlines")
-		return feature, xyPairs, 0.0, errors.Wrap(err, 0)
+		err = errors.New(noCutLineError)
+		return feature, xs, rec, errors.Wrap(err, 0)
 	}
 
 	xyzLineString := gdal.Create(gdal.GT_LineString25D)
-	for _, pair := range xyPairs {
-		xyzLineString.AddPoint(pair[0], pair[1], 0.0)
-	}
-	lenCutLine := xyzLineString.Length()
-
-	mzPairs, err := getDataPairsfromTextBlock("#Sta/Elev", sc, 80, 8)
-	if err != nil {
-		return feature, xyPairs, mzPairs[0][0], errors.Wrap(err, 0)
-	}
-
-	if len(mzPairs) >= 2 {
-		lenProfile := mzPairs[len(mzPairs)-1][0] - mzPairs[0][0]
-		if math.Abs(lenProfile-lenCutLine) <= 0.1 {
-			xyzPoints := attributeZ(xyPairs, mzPairs)
-			xyzLineString = gdal.Create(gdal.GT_LineString25D)
-			for _, point := range xyzPoints {
-				xyzLineString.AddPoint(point.x, point.y, point.z)
-			}
-			feature.Fields["CutLineProfileMatch"] = true
+	if rec.hasProfile() {
+		for _, point := range attributeZ(xyPairs, xs.StationElevation) {
+			xyzLineString.AddPoint(point.x, point.y, point.z)
+		}
+	} else {
+		for _, pair := range xyPairs {
+			xyzLineString.AddPoint(pair[0], pair[1], 0.0)
 		}
 	}
 
-	xyzLineString.Transform(transform)
-	// This is a temporary fix since the x and y values need to be flipped
-	yxzLineString := flipXYLineString25D(xyzLineString)
+	transform.apply(xyzLineString)
 
-	multiLineString := yxzLineString.ForceToMultiLineString()
+	multiLineString := xyzLineString.ForceToMultiLineString()
 	wkb, err := multiLineString.ToWKB()
 	if err != nil {
-		return feature, xyPairs, mzPairs[0][0], errors.Wrap(err, 0)
+		return feature, xs, rec, errors.Wrap(err, 0)
 	}
 	feature.Geometry = wkb
-	return feature, xyPairs, mzPairs[0][0], nil
+	return feature, xs, rec, nil
 }
 
-func getBanks(line string, transform gdal.CoordinateTransform, xsFeature VectorFeature, xyPairs [][2]float64, startingStation float64) ([]VectorFeature, error) {
+// xsStationPoint returns a point on the cross-section cut line at the given cross-section station
+func xsStationPoint(xs crossSections, station float64, transform gisTransform) ([]uint8, error) {
+	startingStation := xs.StationElevation[0][0]
+
+	xy := interpXY(xs.CutLine, station-startingStation)
+	xyPoint := gdal.Create(gdal.GT_Point)
+	xyPoint.AddPoint2D(xy[0], xy[1])
+	transform.apply(xyPoint)
+	multiPoint := xyPoint.ForceToMultiPoint()
+	wkb, err := multiPoint.ToWKB()
+	if err != nil {
+		return wkb, errors.Wrap(err, 0)
+	}
+	return wkb, nil
+}
+
+func getBanks(xs crossSections, transform gisTransform, xsFeature VectorFeature) ([]VectorFeature, error) {
 	layer := []VectorFeature{}
+	if !xs.hasBanks() {
+		return layer, nil
+	}
 
-	bankStations := strings.Split(rightofEquals(line), ",")
-	for _, s := range bankStations {
-		feature := VectorFeature{FeatureName: strings.TrimSpace(s), Fields: map[string]interface{}{}}
+	for n, bankStation := range []float64{xs.LeftBank, xs.RightBank} {
+		feature := VectorFeature{FeatureName: xs.BankStations[n], Fields: map[string]interface{}{}}
 		feature.Fields["RiverReachName"] = xsFeature.Fields["RiverReachName"]
 		feature.Fields["xsName"] = xsFeature.FeatureName
-		bankStation, err := parseFloat(s, 64)
+		wkb, err := xsStationPoint(xs, bankStation, transform)
 		if err != nil {
 			return layer, errors.Wrap(err, 0)
 		}
-		bankXY := interpXY(xyPairs, bankStation-startingStation)
-		xyPoint := gdal.Create(gdal.GT_Point)
-		xyPoint.AddPoint2D(bankXY[0], bankXY[1])
-		xyPoint.Transform(transform)
-		// This is a temporary fix since the x and y values need to be flipped
-		yxPoint := flipXYPoint(xyPoint)
-		multiPoint := yxPoint.ForceToMultiPoint()
-		wkb, err := multiPoint.ToWKB()
+		feature.Geometry = wkb
+		layer = append(layer, feature)
+	}
+	return layer, nil
+}
+
+// Extract left and right levees of a cross section and return as Vector Features
+func getLeveePoints(xs crossSections, transform gisTransform, xsFeature VectorFeature) ([]VectorFeature, error) {
+	layer := []VectorFeature{}
+
+	sides := []string{"Left", "Right"}
+	for n, levee := range []*levees{xs.LeftLevee, xs.RightLevee} {
+		if levee == nil {
+			continue
+		}
+		feature := VectorFeature{FeatureName: sides[n], Fields: map[string]interface{}{}}
+		feature.Fields["RiverReachName"] = xsFeature.Fields["RiverReachName"]
+		feature.Fields["xsName"] = xsFeature.FeatureName
+		feature.Fields["Station"] = levee.Station
+		feature.Fields["Elevation"] = levee.Elevation
+		wkb, err := xsStationPoint(xs, levee.Station, transform)
 		if err != nil {
 			return layer, errors.Wrap(err, 0)
 		}
@@ -332,17 +500,18 @@ func getBanks(line string, transform gdal.CoordinateTransform, xsFeature VectorF
 	return layer, nil
 }
 
-func getArea(sc *bufio.Scanner, transform gdal.CoordinateTransform) (VectorFeature, string, error) {
+// Returns when a new geometry element is encountered, in which case the next scan must be skipped.
+func getArea(sc *bufio.Scanner, transform gisTransform) (VectorFeature, string, bool, error) {
 	feature := VectorFeature{FeatureName: strings.TrimSpace(strings.Split(rightofEquals(sc.Text()), ",")[0])}
 
 	xyPairs, err := getDataPairsfromTextBlock("Storage Area Surface Line=", sc, 32, 16)
 	if err != nil {
-		return feature, "", errors.Wrap(err, 0)
+		return feature, "", false, errors.Wrap(err, 0)
 	}
 
-	is2D, err := getAreaType(sc)
+	is2D, skipScan, err := getAreaType(sc)
 	if err != nil {
-		return feature, "", errors.Wrap(err, 0)
+		return feature, "", skipScan, errors.Wrap(err, 0)
 	}
 
 	xyLinearRing := gdal.Create(gdal.GT_LinearRing)
@@ -350,51 +519,56 @@ func getArea(sc *bufio.Scanner, transform gdal.CoordinateTransform) (VectorFeatu
 		xyLinearRing.AddPoint2D(pair[0], pair[1])
 	}
 
-	xyLinearRing.Transform(transform)
-	// This is a temporary fix since the x and y values need to be flipped:
-	yxLinearRing := flipXYLinearRing(xyLinearRing)
+	transform.apply(xyLinearRing)
 
-	yxPolygon := gdal.Create(gdal.GT_Polygon)
-	yxPolygon.AddGeometry(yxLinearRing)
-	yxMultiPolygon := yxPolygon.ForceToMultiPolygon()
-	wkb, err := yxMultiPolygon.ToWKB()
+	xyPolygon := gdal.Create(gdal.GT_Polygon)
+	xyPolygon.AddGeometry(xyLinearRing)
+	xyMultiPolygon := xyPolygon.ForceToMultiPolygon()
+	wkb, err := xyMultiPolygon.ToWKB()
 	if err != nil {
-		return feature, is2D, errors.Wrap(err, 0)
+		return feature, is2D, skipScan, errors.Wrap(err, 0)
 	}
 	feature.Geometry = wkb
-	return feature, is2D, nil
+	return feature, is2D, skipScan, nil
 }
 
-func getAreaType(sc *bufio.Scanner) (string, error) {
-	is2D := ""
-
+// Get the type of an area, "0" for a storage area and "-1" for a 2D area.
+// Models older than HEC-RAS 5.0 do not flag storage areas, so an area without type is a storage area.
+// Returns when a new geometry element is encountered, in which case the next scan must be skipped.
+func getAreaType(sc *bufio.Scanner) (string, bool, error) {
 	for sc.Scan() {
 		line := sc.Text()
 		if strings.HasPrefix(line, "Storage Area Is2D=") {
-			is2D = rightofEquals(line)
+			is2D := rightofEquals(line)
 			if is2D != "0" && is2D != "-1" {
-				return "", errors.New("Cannot determine if area is storage area or 2D area.")
+				return "", false, errors.New("Cannot determine if area is storage area or 2D area.")
 			}
-			return is2D, nil
+			return is2D, false, nil
+		}
+		if stringInSlice(leftofEquals(line), geomElementsPrefix[:]) {
+			// a new HEC RAS element has been encountered, skip next scan and return
+			return "0", true, nil
 		}
 	}
-
-	return "", errors.New("Failed to parse area type.")
+	return "0", false, nil
 }
 
-// Extract name and geometry from BreakLine text block and return as Vector Feature
-func getBreakLine(sc *bufio.Scanner, transform gdal.CoordinateTransform) (VectorFeature, error) {
-	blName := rightofEquals(sc.Text())
-	feature := VectorFeature{FeatureName: blName}
-
-	xyPairs, err := getDataPairsfromTextBlock("BreakLine Polyline=", sc, 64, 16)
+// Extract name, attributes and geometry from BreakLine text block and return as Vector Feature
+func getBreakLine(sc *bufio.Scanner, transform gisTransform) (VectorFeature, bool, error) {
+	blName, breakLine, skipScan, err := getBreakLineData(sc)
+	feature := VectorFeature{FeatureName: blName, Fields: map[string]interface{}{}}
 	if err != nil {
-		return feature, errors.Wrap(err, 0)
+		return feature, skipScan, errors.Wrap(err, 0)
 	}
+	feature.Fields["NearSpacing"] = breakLine.NearSpacing
+	feature.Fields["FarSpacing"] = breakLine.FarSpacing
+	feature.Fields["NearRepeats"] = breakLine.NearRepeats
+	feature.Fields["Enforce1D"] = breakLine.Enforce1D
 
+	xyPairs := breakLine.Polyline
 	// If less than 2 xyPairs, it is not a valid line.
 	if len(xyPairs) < 2 {
-		return feature, errors.New("Invalid Line Geometry")
+		return feature, skipScan, errors.New("Invalid Line Geometry")
 	}
 
 	xyLineString := gdal.Create(gdal.GT_LineString)
@@ -402,22 +576,55 @@ func getBreakLine(sc *bufio.Scanner, transform gdal.CoordinateTransform) (Vector
 		xyLineString.AddPoint2D(pair[0], pair[1])
 	}
 
-	xyLineString.Transform(transform)
-	// This is a temporary fix since the x and y values need to be flipped:
-	yxLineString := flipXYLineString(xyLineString)
+	transform.apply(xyLineString)
 
-	multiLineString := yxLineString.ForceToMultiLineString()
+	multiLineString := xyLineString.ForceToMultiLineString()
 
 	wkb, err := multiLineString.ToWKB()
 	if err != nil {
-		return feature, errors.Wrap(err, 0)
+		return feature, skipScan, errors.Wrap(err, 0)
 	}
 	feature.Geometry = wkb
-	return feature, nil
+	return feature, skipScan, nil
+}
+
+// Extract name, attributes and geometry from Refinement Region text block and return as Vector Feature
+func getRefinementRegion(sc *bufio.Scanner, transform gisTransform) (VectorFeature, bool, error) {
+	regionName, region, skipScan, err := getRefinementRegionData(sc)
+	feature := VectorFeature{FeatureName: regionName, Fields: map[string]interface{}{}}
+	if err != nil {
+		return feature, skipScan, errors.Wrap(err, 0)
+	}
+	feature.Fields["CellSizeX"] = region.CellSizeX
+	feature.Fields["CellSizeY"] = region.CellSizeY
+	feature.Fields["NearRepeats"] = region.NearRepeats
+
+	// If less than 3 xyPairs, it is not a valid polygon.
+	if len(region.Polygon) < 3 {
+		return feature, skipScan, errors.New("Invalid Polygon Geometry")
+	}
+
+	xyLinearRing := gdal.Create(gdal.GT_LinearRing)
+	for _, pair := range region.Polygon {
+		xyLinearRing.AddPoint2D(pair[0], pair[1])
+	}
+	xyLinearRing.AddPoint2D(region.Polygon[0][0], region.Polygon[0][1])
+
+	transform.apply(xyLinearRing)
+
+	xyPolygon := gdal.Create(gdal.GT_Polygon)
+	xyPolygon.AddGeometry(xyLinearRing)
+	xyMultiPolygon := xyPolygon.ForceToMultiPolygon()
+	wkb, err := xyMultiPolygon.ToWKB()
+	if err != nil {
+		return feature, skipScan, errors.Wrap(err, 0)
+	}
+	feature.Geometry = wkb
+	return feature, skipScan, nil
 }
 
 // Extract name and geometry from Boundary Condition text block and return as Vector Feature
-func getBCLine(sc *bufio.Scanner, transform gdal.CoordinateTransform) (VectorFeature, error) {
+func getBCLine(sc *bufio.Scanner, transform gisTransform) (VectorFeature, error) {
 	bcName := rightofEquals(sc.Text())
 
 	feature := VectorFeature{
@@ -446,11 +653,9 @@ func getBCLine(sc *bufio.Scanner, transform gdal.CoordinateTransform) (VectorFea
 		xyLineString.AddPoint2D(pair[0], pair[1])
 	}
 
-	xyLineString.Transform(transform)
-	// This is a temporary fix since the x and y values need to be flipped:
-	yxLineString := flipXYLineString(xyLineString)
+	transform.apply(xyLineString)
 
-	multiLineString := yxLineString.ForceToMultiLineString()
+	multiLineString := xyLineString.ForceToMultiLineString()
 
 	wkb, err := multiLineString.ToWKB()
 	if err != nil {
@@ -474,7 +679,7 @@ func getBCArea(sc *bufio.Scanner) (string, error) {
 }
 
 // Extract name and geometry from Connection text block and return as Vector Feature
-func getConnectionLine(sc *bufio.Scanner, transform gdal.CoordinateTransform) (VectorFeature, error) {
+func getConnectionLine(sc *bufio.Scanner, transform gisTransform) (VectorFeature, error) {
 	feature := VectorFeature{
 		FeatureName: strings.TrimSpace(strings.Split(rightofEquals(sc.Text()), ",")[0]),
 		Fields:      map[string]interface{}{},
@@ -495,11 +700,9 @@ func getConnectionLine(sc *bufio.Scanner, transform gdal.CoordinateTransform) (V
 		xyLineString.AddPoint2D(pair[0], pair[1])
 	}
 
-	xyLineString.Transform(transform)
-	// This is a temporary fix since the x and y values need to be flipped:
-	yxLineString := flipXYLineString(xyLineString)
+	transform.apply(xyLineString)
 
-	multiLineString := yxLineString.ForceToMultiLineString()
+	multiLineString := xyLineString.ForceToMultiLineString()
 
 	wkb, err := multiLineString.ToWKB()
 	if err != nil {
@@ -536,11 +739,101 @@ func getConnArea(sc *bufio.Scanner) (string, string, error) {
 	return "", "", errors.New("Failed to parse Connection Up/Dn Areas.")
 }
 
+// Extract name and location from Junction text block and return as Vector Feature
+func getJunctionPoint(sc *bufio.Scanner, transform gisTransform) (VectorFeature, bool, error) {
+	name, junction, skipScan, err := getJunction(sc)
+	feature := VectorFeature{FeatureName: name, Fields: map[string]interface{}{}}
+	if err != nil {
+		return feature, skipScan, errors.Wrap(err, 0)
+	}
+	feature.Fields["UpstreamReaches"] = strings.Join(junction.UpstreamReaches, "; ")
+	feature.Fields["DownstreamReaches"] = strings.Join(junction.DownstreamReaches, "; ")
+
+	xyPoint := gdal.Create(gdal.GT_Point)
+	xyPoint.AddPoint2D(junction.X, junction.Y)
+	transform.apply(xyPoint)
+	multiPoint := xyPoint.ForceToMultiPoint()
+	wkb, err := multiPoint.ToWKB()
+	if err != nil {
+		return feature, skipScan, errors.Wrap(err, 0)
+	}
+	feature.Geometry = wkb
+	return feature, skipScan, nil
+}
+
+func getPumpStationPoint(sc *bufio.Scanner, transform gisTransform) (VectorFeature, bool, error) {
+	name, pump, skipScan, err := getPumpStation(sc)
+	feature := VectorFeature{FeatureName: name, Fields: map[string]interface{}{}}
+	if err != nil {
+		return feature, skipScan, errors.Wrap(err, 0)
+	}
+	feature.Fields["Description"] = pump.Description
+	feature.Fields["From"] = pumpLocationName(pump.From)
+	feature.Fields["To"] = pumpLocationName(pump.To)
+	feature.Fields["NumPumpGroups"] = pump.NumGroups
+
+	xyPoint := gdal.Create(gdal.GT_Point)
+	xyPoint.AddPoint2D(pump.X, pump.Y)
+	transform.apply(xyPoint)
+	multiPoint := xyPoint.ForceToMultiPoint()
+	wkb, err := multiPoint.ToWKB()
+	if err != nil {
+		return feature, skipScan, errors.Wrap(err, 0)
+	}
+	feature.Geometry = wkb
+	return feature, skipScan, nil
+}
+
+// Name of the reach river station or area a pump station pumps from or to
+func pumpLocationName(location pumpLocation) string {
+	if location.Reach != "" {
+		return fmt.Sprintf("%s, %s", location.Reach, location.RS)
+	}
+	return location.StorageArea
+}
+
+// Extract 2D area cell centers as a multipoint feature along with the mesh statistics
+func getMeshPoints(sc *bufio.Scanner, transform gisTransform, areaName string) (VectorFeature, error) {
+	feature := VectorFeature{FeatureName: areaName, Fields: map[string]interface{}{}}
+
+	xyPairs, err := getDataPairsfromTextBlock("Storage Area 2D Points=", sc, 64, 16)
+	if err != nil {
+		return feature, errors.Wrap(err, 0)
+	}
+
+	stats := meshStatistics(xyPairs)
+	feature.Fields["NumCells"] = len(xyPairs)
+	feature.Fields["NominalSpacing"] = stats.NominalSpacing
+	feature.Fields["MinSpacing"] = stats.MinSpacing
+	feature.Fields["MaxSpacing"] = stats.MaxSpacing
+	feature.Fields["MedianSpacing"] = stats.MedianSpacing
+
+	xyMultiPoint := gdal.Create(gdal.GT_MultiPoint)
+	for _, pair := range xyPairs {
+		xyPoint := gdal.Create(gdal.GT_Point)
+		xyPoint.AddPoint2D(pair[0], pair[1])
+		xyMultiPoint.AddGeometryDirectly(xyPoint)
+	}
+	transform.apply(xyMultiPoint)
+
+	wkb, err := xyMultiPoint.ToWKB()
+	if err != nil {
+		return feature, errors.Wrap(err, 0)
+	}
+	feature.Geometry = wkb
+	return feature, nil
+}
+
 // GetGeospatialData ...
-func GetGeospatialData(gd *GeoData, fs filestore.FileStore, geomFilePath string, sourceCRS string, destinationCRS int) error {
+func GetGeospatialData(gd *GeoData, fs filestore.FileStore, geomFilePath string, sourceCRS string, destinationCRS string, opts GeoOptions) error {
+	if !opts.wantGeomFile(geomFilePath) {
+		return nil
+	}
+
 	geomFileName := filepath.Base(geomFilePath)
 	f := Features{}
 	riverReachName := ""
+	areaName := ""
 
 	file, err := fs.GetObject(geomFilePath)
 	if err != nil {
@@ -555,38 +848,152 @@ func GetGeospatialData(gd *GeoData, fs filestore.FileStore, geomFilePath string,
 		return errors.Wrap(err, 0)
 	}
 
-	for sc.Scan() {
+	counts := make(map[string]VertexCount)
+	// filter and simplify features, then keep them or pass them to the sink
+	add := func(layer string, features ...VectorFeature) error {
+		for _, feature := range features {
+			if opts.BBox != nil {
+				inside, err := intersectsBBox(feature.Geometry, *opts.BBox)
+				if err != nil {
+					return errors.Wrap(err, 0)
+				}
+				if !inside {
+					continue
+				}
+			}
+
+			if opts.Simplify > 0 || opts.Precision != nil {
+				wkb, before, after, err := simplifyWKB(feature.Geometry, opts.Simplify, opts.Precision)
+				if err != nil {
+					return errors.Wrap(err, 0)
+				}
+				feature.Geometry = wkb
+				count := counts[layer]
+				count.Before += before
+				count.After += after
+				counts[layer] = count
+			}
+
+			if opts.Sink != nil {
+				if err := opts.Sink(geomFileName, layer, feature); err != nil {
+					return errors.Wrap(err, 0)
+				}
+				continue
+			}
+			f.add(layer, feature)
+		}
+		return nil
+	}
+
+	eof := !sc.Scan()
+	for !eof {
+		skipScan := false
 		line := sc.Text()
 
 		switch {
+		case strings.HasPrefix(line, "River Reach=") && !opts.wantLayer("Rivers"):
+			// the reach name is still needed by the cross-sections
+			riverReach := strings.Split(rightofEquals(line), ",")
+			riverReachName = fmt.Sprintf("%s, %s", strings.TrimSpace(riverReach[0]), strings.TrimSpace(riverReach[1]))
+
 		case strings.HasPrefix(line, "River Reach="):
-			riverFeature, err := getRiverCenterline(sc, transform)
+			riverFeature, ss, err := getRiverCenterline(sc, transform)
+			skipScan = ss
+			riverReachName = riverFeature.FeatureName
+			switch {
+			case err != nil:
+				switch {
+				case err.Error() == "Invalid Line Geometry":
+					log.Println("Skipped", riverFeature.FeatureName, err.Error(), "Geom File:", filepath.Ext(geomFilePath))
+				default:
+					return errors.Wrap(err, 0)
+				}
+			default:
+				if err := add("Rivers", riverFeature); err != nil {
+					return errors.Wrap(err, 0)
+				}
+			}
+
+		case strings.HasPrefix(line, "Storage Area=") && !opts.wantLayer("StorageAreas") && !opts.wantLayer("TwoDAreas"):
+			// the area name is still needed by the mesh points
+			areaName = strings.TrimSpace(strings.Split(rightofEquals(line), ",")[0])
+
+		case strings.HasPrefix(line, "Storage Area="):
+			storageAreaFeature, aType, ss, err := getArea(sc, transform)
+			skipScan = ss
 			if err != nil {
 				return errors.Wrap(err, 0)
 			}
-			f.Rivers = append(f.Rivers, riverFeature)
-			riverReachName = riverFeature.FeatureName
+			if aType == "0" && opts.wantLayer("StorageAreas") {
+				if err := add("StorageAreas", storageAreaFeature); err != nil {
+					return errors.Wrap(err, 0)
+				}
+			} else if aType == "-1" && opts.wantLayer("TwoDAreas") {
+				if err := add("TwoDAreas", storageAreaFeature); err != nil {
+					return errors.Wrap(err, 0)
+				}
+			}
+			areaName = storageAreaFeature.FeatureName
 
-		case strings.HasPrefix(line, "Storage Area="):
-			storageAreaFeature, aType, err := getArea(sc, transform)
+		case opts.wantLayer("MeshPoints") && strings.HasPrefix(line, "Storage Area 2D Points="):
+			meshFeature, err := getMeshPoints(sc, transform, areaName)
+			if err != nil {
+				return errors.Wrap(err, 0)
+			}
+			if err := add("MeshPoints", meshFeature); err != nil {
+				return errors.Wrap(err, 0)
+			}
+
+		case strings.HasPrefix(line, "Type RM Length L Ch R = 1") && (opts.wantLayer("XS") || opts.wantLayer("Banks") || opts.wantLayer("Levees")):
+			xsFeature, bankLayer, leveeLayer, ss, err := getXSBanks(sc, transform, riverReachName, opts)
+			skipScan = ss
+			if err != nil && err.Error() == noCutLineError && opts.SourceCRS != "" {
+				// older geometry files do not always have cut lines
+				log.Println("Skipped", xsFeature.FeatureName, err.Error(), "Geom File:", filepath.Ext(geomFilePath))
+				break
+			}
+			if err != nil {
+				return errors.Wrap(err, 0)
+			}
+			if opts.wantLayer("XS") {
+				if err := add("XS", xsFeature); err != nil {
+					return errors.Wrap(err, 0)
+				}
+			}
+			if opts.wantLayer("Banks") {
+				if err := add("Banks", bankLayer...); err != nil {
+					return errors.Wrap(err, 0)
+				}
+			}
+			if opts.wantLayer("Levees") {
+				if err := add("Levees", leveeLayer...); err != nil {
+					return errors.Wrap(err, 0)
+				}
+			}
+
+		case strings.HasPrefix(line, "Junct Name=") && opts.wantLayer("Junctions"):
+			junctionFeature, ss, err := getJunctionPoint(sc, transform)
+			skipScan = ss
 			if err != nil {
 				return errors.Wrap(err, 0)
 			}
-			if aType == "0" {
-				f.StorageAreas = append(f.StorageAreas, storageAreaFeature)
-			} else if aType == "-1" {
-				f.TwoDAreas = append(f.TwoDAreas, storageAreaFeature)
+			if err := add("Junctions", junctionFeature); err != nil {
+				return errors.Wrap(err, 0)
 			}
-		case strings.HasPrefix(line, "Type RM Length L Ch R = 1"):
-			xsFeature, bankLayer, err := getXSBanks(sc, transform, riverReachName)
+
+		case strings.HasPrefix(line, "Pump Station=") && opts.wantLayer("PumpStations"):
+			pumpFeature, ss, err := getPumpStationPoint(sc, transform)
+			skipScan = ss
 			if err != nil {
 				return errors.Wrap(err, 0)
 			}
-			f.XS = append(f.XS, xsFeature)
-			f.Banks = append(f.Banks, bankLayer...)
+			if err := add("PumpStations", pumpFeature); err != nil {
+				return errors.Wrap(err, 0)
+			}
 
-		case strings.HasPrefix(line, "BreakLine Name="):
-			blFeature, err := getBreakLine(sc, transform)
+		case strings.HasPrefix(line, "BreakLine Name=") && opts.wantLayer("BreakLines"):
+			blFeature, ss, err := getBreakLine(sc, transform)
+			skipScan = ss
 			switch {
 			case err != nil:
 				switch {
@@ -596,10 +1003,29 @@ func GetGeospatialData(gd *GeoData, fs filestore.FileStore, geomFilePath string,
 					return errors.Wrap(err, 0)
 				}
 			default:
-				f.BreakLines = append(f.BreakLines, blFeature)
+				if err := add("BreakLines", blFeature); err != nil {
+					return errors.Wrap(err, 0)
+				}
+			}
+
+		case strings.HasPrefix(line, "Refinement Region Name=") && opts.wantLayer("RefinementRegions"):
+			regionFeature, ss, err := getRefinementRegion(sc, transform)
+			skipScan = ss
+			switch {
+			case err != nil:
+				switch {
+				case err.Error() == "Invalid Polygon Geometry":
+					log.Println("Skipped", regionFeature.FeatureName, err.Error(), "Geom File:", filepath.Ext(geomFilePath))
+				default:
+					return errors.Wrap(err, 0)
+				}
+			default:
+				if err := add("RefinementRegions", regionFeature); err != nil {
+					return errors.Wrap(err, 0)
+				}
 			}
 
-		case strings.HasPrefix(line, "BC Line Name="):
+		case strings.HasPrefix(line, "BC Line Name=") && opts.wantLayer("BCLines"):
 			bcFeature, err := getBCLine(sc, transform)
 			switch {
 			case err != nil:
@@ -610,10 +1036,12 @@ func GetGeospatialData(gd *GeoData, fs filestore.FileStore, geomFilePath string,
 					return errors.Wrap(err, 0)
 				}
 			default:
-				f.BCLines = append(f.BCLines, bcFeature)
+				if err := add("BCLines", bcFeature); err != nil {
+					return errors.Wrap(err, 0)
+				}
 			}
 
-		case strings.HasPrefix(line, "Connection="):
+		case strings.HasPrefix(line, "Connection=") && opts.wantLayer("Connections"):
 			connFeature, err := getConnectionLine(sc, transform)
 			switch {
 			case err != nil:
@@ -624,10 +1052,60 @@ func GetGeospatialData(gd *GeoData, fs filestore.FileStore, geomFilePath string,
 					return errors.Wrap(err, 0)
 				}
 			default:
-				f.Connections = append(f.Connections, connFeature)
+				if err := add("Connections", connFeature); err != nil {
+					return errors.Wrap(err, 0)
+				}
+			}
+
+		}
+
+		// if a new RAS element is encountered during the functions call, scanning again will skip that element, therefore skip scan
+		if !skipScan {
+			eof = !sc.Scan()
+		}
+	}
+
+	if opts.wantLayer("HydraulicStructures") || opts.wantLayer("BankLines") || opts.wantLayer("FlowPaths") {
+		// structures, bank lines and flow paths are located with the cross-sections of their reach, read reach by reach
+		err := getReachGeometries(fs, geomFilePath, func(reach reachGeometry) error {
+			if opts.wantLayer("HydraulicStructures") {
+				structures, err := getReachStructures(reach, transform)
+				if err != nil {
+					return errors.Wrap(err, 0)
+				}
+				if err := add("HydraulicStructures", structures...); err != nil {
+					return errors.Wrap(err, 0)
+				}
+			}
+
+			if opts.wantLayer("BankLines") || opts.wantLayer("FlowPaths") {
+				bankLines, flowPaths, err := getReachBankLines(reach, transform, opts)
+				if err != nil {
+					return errors.Wrap(err, 0)
+				}
+				if opts.wantLayer("BankLines") {
+					if err := add("BankLines", bankLines...); err != nil {
+						return errors.Wrap(err, 0)
+					}
+				}
+				if opts.wantLayer("FlowPaths") {
+					if err := add("FlowPaths", flowPaths...); err != nil {
+						return errors.Wrap(err, 0)
+					}
+				}
 			}
+			return nil
+		})
+		if err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
 
+	if opts.Simplify > 0 || opts.Precision != nil {
+		if gd.VertexCounts == nil {
+			gd.VertexCounts = make(map[string]map[string]VertexCount)
 		}
+		gd.VertexCounts[geomFileName] = counts
 	}
 
 	gd.Features[geomFileName] = f
@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"bufio"
+	"strings"
+	"testing"
+
+	"github.com/dewberry/gdal"
+)
+
+func TestWantLayer(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     GeoOptions
+		layer    string
+		expected bool
+	}{
+		{"all layers by default", GeoOptions{}, "XS", true},
+		{"mesh points not requested", GeoOptions{}, "MeshPoints", false},
+		{"mesh points requested", GeoOptions{MeshPoints: true}, "MeshPoints", true},
+		{"listed layer", GeoOptions{Layers: []string{"Rivers", "XS"}}, "XS", true},
+		{"unlisted layer", GeoOptions{Layers: []string{"Rivers", "XS"}}, "Banks", false},
+		{"listed mesh points", GeoOptions{Layers: []string{"MeshPoints"}}, "MeshPoints", true},
+		{"mesh points requested with other layers listed", GeoOptions{MeshPoints: true, Layers: []string{"XS"}}, "MeshPoints", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.opts.wantLayer(tc.layer); got != tc.expected {
+				t.Errorf("got %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestWantGeomFile(t *testing.T) {
+	tests := []struct {
+		geomFiles []string
+		path      string
+		expected  bool
+	}{
+		{nil, "/models/ras/model.g01", true},
+		{[]string{"g01"}, "/models/ras/model.g01", true},
+		{[]string{".G02", "g01"}, "/models/ras/model.g01", true},
+		{[]string{"g02"}, "/models/ras/model.g01", false},
+	}
+	for _, tc := range tests {
+		if got := (GeoOptions{GeomFiles: tc.geomFiles}).wantGeomFile(tc.path); got != tc.expected {
+			t.Errorf("wantGeomFile(%q) with %v: got %v, want %v", tc.path, tc.geomFiles, got, tc.expected)
+		}
+	}
+}
+
+func TestIntersectsBBox(t *testing.T) {
+	lineWKB := func(xyPairs [][2]float64) []uint8 {
+		lineString := gdal.Create(gdal.GT_LineString)
+		for _, pair := range xyPairs {
+			lineString.AddPoint2D(pair[0], pair[1])
+		}
+		wkb, err := lineString.ForceToMultiLineString().ToWKB()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return wkb
+	}
+	bbox := [4]float64{0, 0, 10, 10}
+
+	tests := []struct {
+		name     string
+		wkb      []uint8
+		expected bool
+	}{
+		{"inside", lineWKB([][2]float64{{2, 2}, {4, 4}}), true},
+		{"crossing", lineWKB([][2]float64{{-5, 5}, {5, 5}}), true},
+		{"touching a corner", lineWKB([][2]float64{{10, 10}, {20, 20}}), true},
+		{"around", lineWKB([][2]float64{{-5, -5}, {15, 15}}), true},
+		{"right of the box", lineWKB([][2]float64{{11, 0}, {20, 5}}), false},
+		{"below the box", lineWKB([][2]float64{{0, -5}, {10, -1}}), false},
+		{"no geometry", nil, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inside, err := intersectsBBox(tc.wkb, bbox)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if inside != tc.expected {
+				t.Errorf("got %v, want %v", inside, tc.expected)
+			}
+		})
+	}
+
+	if _, err := intersectsBBox([]uint8{1, 2, 3}, bbox); err == nil {
+		t.Error("expected an error for an invalid WKB geometry")
+	}
+}
+
+func TestGetAreaType(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		is2D     string
+		skipScan bool
+		next     string
+	}{
+		{"2D area", "Storage Area Is2D=-1\nStorage Area Point Generation Data=,,100,100", "-1", false, "Storage Area Point Generation Data=,,100,100"},
+		{"storage area", "Storage Area Type= 0 \nStorage Area Is2D=0", "0", false, ""},
+		{"pre 5.0 storage area followed by an element", "Storage Area Type= 0 \nStorage Area=Lake,100,200", "0", true, "Storage Area=Lake,100,200"},
+		{"pre 5.0 storage area at end of file", "Storage Area Type= 0 ", "0", false, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			sc := bufio.NewScanner(strings.NewReader(tc.text))
+			is2D, skipScan, err := getAreaType(sc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if is2D != tc.is2D || skipScan != tc.skipScan {
+				t.Errorf("got (%q, %v), want (%q, %v)", is2D, skipScan, tc.is2D, tc.skipScan)
+			}
+			if tc.skipScan && sc.Text() != tc.next {
+				t.Errorf("scanner at %q, want %q", sc.Text(), tc.next)
+			}
+		})
+	}
+
+	sc := bufio.NewScanner(strings.NewReader("Storage Area Is2D=1"))
+	if _, _, err := getAreaType(sc); err == nil {
+		t.Error("expected an error for an unknown area type")
+	}
+}
@@ -0,0 +1,173 @@
+package tools
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-errors/errors" // warning: replaces standard errors
+)
+
+// Store HEC-RAS Junctions
+type Junction struct {
+	Description       string
+	X                 float64
+	Y                 float64
+	UpstreamReaches   []string  `json:"Upstream Reaches"`
+	DownstreamReaches []string  `json:"Downstream Reaches"`
+	Lengths           []float64 `json:"Junction Lengths"`
+	Angles            []float64 `json:"Junction Angles"`
+}
+
+// Store the connectivity of HEC-RAS reaches.
+// Nodes are junctions and reach ends, edges are reaches.
+type ReachNetwork struct {
+	Nodes []NetworkNode
+	Edges []NetworkEdge
+}
+
+// Junction or end of a reach that is not connected to a junction
+type NetworkNode struct {
+	Name string
+	Type string
+}
+
+// Reach flowing from its upstream node to its downstream node
+type NetworkEdge struct {
+	Reach  string
+	UpNode string `json:"Upstream Node"`
+	DnNode string `json:"Downstream Node"`
+}
+
+// Extract Junction data from a Junction text block.
+// Returns when a new geometry element is encountered, in which case the next scan must be skipped.
+func getJunction(sc *bufio.Scanner) (string, Junction, bool, error) {
+	junction := Junction{}
+	name := strings.TrimSpace(rightofEquals(sc.Text()))
+
+	for sc.Scan() {
+		line := sc.Text()
+		if stringInSlice(leftofEquals(line), geomElementsPrefix[:]) {
+			// a new HEC RAS element has been encountered, skip next scan and return
+			return name, junction, true, nil
+		}
+
+		switch {
+		case strings.HasPrefix(line, "Junct Desc="):
+			junction.Description = strings.Trim(rightofEquals(line), ", ")
+
+		case strings.HasPrefix(line, "Junct X Y & Text X Y="):
+			xy := strings.Split(rightofEquals(line), ",")
+			if len(xy) < 2 {
+				return name, junction, false, errors.Errorf("Failed to parse junction coordinates at line '%s'", line)
+			}
+			x, err := stringtoFloat(xy[0])
+			if err != nil {
+				return name, junction, false, errors.Wrap(err, 0)
+			}
+			y, err := stringtoFloat(xy[1])
+			if err != nil {
+				return name, junction, false, errors.Wrap(err, 0)
+			}
+			junction.X, junction.Y = x, y
+
+		case strings.HasPrefix(line, "Up River,Reach="):
+			junction.UpstreamReaches = append(junction.UpstreamReaches, junctionReachName(line))
+
+		case strings.HasPrefix(line, "Dn River,Reach="):
+			junction.DownstreamReaches = append(junction.DownstreamReaches, junctionReachName(line))
+
+		case strings.HasPrefix(line, "Junc L&A="):
+			lengthAngle := strings.Split(rightofEquals(line), ",")
+			length, err := stringtoFloat(lengthAngle[0])
+			if err != nil {
+				return name, junction, false, errors.Wrap(err, 0)
+			}
+			junction.Lengths = append(junction.Lengths, length)
+
+			var angle float64
+			if len(lengthAngle) > 1 {
+				angle, err = stringtoFloat(lengthAngle[1])
+				if err != nil {
+					return name, junction, false, errors.Wrap(err, 0)
+				}
+			}
+			junction.Angles = append(junction.Angles, angle)
+		}
+	}
+	return name, junction, false, nil
+}
+
+// Return river and reach name of a junction's reach in the same format as geospatial rivers
+func junctionReachName(line string) string {
+	riverReach := strings.Split(rightofEquals(line), ",")
+	if len(riverReach) < 2 {
+		return strings.TrimSpace(riverReach[0])
+	}
+	return fmt.Sprintf("%s, %s", strings.TrimSpace(riverReach[0]), strings.TrimSpace(riverReach[1]))
+}
+
+// Extract Junctions Data
+func getJunctionsData(rm *RasModel, fn string, i int) (string, Junction, error) {
+	f, err := rm.FileStore.GetObject(fn)
+	if err != nil {
+		return "", Junction{}, errors.Wrap(err, 0)
+	}
+	defer f.Close()
+
+	jSc := bufio.NewScanner(f)
+
+	ji := 0
+	for jSc.Scan() {
+		ji++
+		if ji == i {
+			name, junction, _, err := getJunction(jSc)
+			if err != nil {
+				return name, junction, errors.Wrap(err, 0)
+			}
+			return name, junction, nil
+		}
+	}
+	return "", Junction{}, errors.New(fmt.Sprintf("Failed to parse junction at geom file line number %v of %v", i, fn))
+}
+
+// Build the reach network from reaches and the junctions connecting them.
+// Reach ends that are not connected to a junction become nodes of their own.
+func buildReachNetwork(reaches []string, junctions map[string]Junction) ReachNetwork {
+	network := ReachNetwork{Nodes: []NetworkNode{}, Edges: []NetworkEdge{}}
+
+	upNodes := make(map[string]string)
+	dnNodes := make(map[string]string)
+	names := make([]string, 0, len(junctions))
+	for name := range junctions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		junction := junctions[name]
+		network.Nodes = append(network.Nodes, NetworkNode{Name: name, Type: "Junction"})
+		for _, reach := range junction.UpstreamReaches {
+			// the junction is downstream of reaches flowing into it
+			dnNodes[reach] = name
+		}
+		for _, reach := range junction.DownstreamReaches {
+			upNodes[reach] = name
+		}
+	}
+
+	for _, reach := range reaches {
+		edge := NetworkEdge{Reach: reach, UpNode: upNodes[reach], DnNode: dnNodes[reach]}
+		if edge.UpNode == "" {
+			edge.UpNode = fmt.Sprintf("%s Upstream End", reach)
+			network.Nodes = append(network.Nodes, NetworkNode{Name: edge.UpNode, Type: "Upstream End"})
+		}
+		if edge.DnNode == "" {
+			edge.DnNode = fmt.Sprintf("%s Downstream End", reach)
+			network.Nodes = append(network.Nodes, NetworkNode{Name: edge.DnNode, Type: "Downstream End"})
+		}
+		network.Edges = append(network.Edges, edge)
+	}
+	return network
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildReachNetwork(t *testing.T) {
+	tests := []struct {
+		name      string
+		reaches   []string
+		junctions map[string]Junction
+		expected  ReachNetwork
+	}{
+		{"empty", []string{}, map[string]Junction{}, ReachNetwork{Nodes: []NetworkNode{}, Edges: []NetworkEdge{}}},
+		{
+			"single reach", []string{"Creek, Main"}, map[string]Junction{},
+			ReachNetwork{
+				Nodes: []NetworkNode{{"Creek, Main Upstream End", "Upstream End"}, {"Creek, Main Downstream End", "Downstream End"}},
+				Edges: []NetworkEdge{{"Creek, Main", "Creek, Main Upstream End", "Creek, Main Downstream End"}},
+			},
+		},
+		{
+			"confluence", []string{"Creek, Upper", "Tributary, Trib", "Creek, Lower"},
+			map[string]Junction{"Confluence": {UpstreamReaches: []string{"Creek, Upper", "Tributary, Trib"}, DownstreamReaches: []string{"Creek, Lower"}}},
+			ReachNetwork{
+				Nodes: []NetworkNode{
+					{"Confluence", "Junction"},
+					{"Creek, Upper Upstream End", "Upstream End"},
+					{"Tributary, Trib Upstream End", "Upstream End"},
+					{"Creek, Lower Downstream End", "Downstream End"},
+				},
+				Edges: []NetworkEdge{
+					{"Creek, Upper", "Creek, Upper Upstream End", "Confluence"},
+					{"Tributary, Trib", "Tributary, Trib Upstream End", "Confluence"},
+					{"Creek, Lower", "Confluence", "Creek, Lower Downstream End"},
+				},
+			},
+		},
+		{
+			"split and rejoin", []string{"Creek, Upper", "Creek, Left", "Creek, Right", "Creek, Lower"},
+			map[string]Junction{
+				"Split": {UpstreamReaches: []string{"Creek, Upper"}, DownstreamReaches: []string{"Creek, Left", "Creek, Right"}},
+				"Join":  {UpstreamReaches: []string{"Creek, Left", "Creek, Right"}, DownstreamReaches: []string{"Creek, Lower"}},
+			},
+			ReachNetwork{
+				Nodes: []NetworkNode{
+					{"Join", "Junction"},
+					{"Split", "Junction"},
+					{"Creek, Upper Upstream End", "Upstream End"},
+					{"Creek, Lower Downstream End", "Downstream End"},
+				},
+				Edges: []NetworkEdge{
+					{"Creek, Upper", "Creek, Upper Upstream End", "Split"},
+					{"Creek, Left", "Split", "Join"},
+					{"Creek, Right", "Split", "Join"},
+					{"Creek, Lower", "Join", "Creek, Lower Downstream End"},
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			network := buildReachNetwork(tc.reaches, tc.junctions)
+			if !reflect.DeepEqual(network, tc.expected) {
+				t.Errorf("got %+v, want %+v", network, tc.expected)
+			}
+		})
+	}
+}
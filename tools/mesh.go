@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"math"
+	"sort"
+)
+
+// Store statistics of a 2D area mesh computed from the distance of each cell center to its nearest neighbor
+type MeshStats struct {
+	NominalSpacing float64 `json:"Nominal Cell Spacing"` // mean nearest neighbor distance
+	MinSpacing     float64 `json:"Min Cell Spacing"`
+	MaxSpacing     float64 `json:"Max Cell Spacing"`
+	MedianSpacing  float64 `json:"Median Cell Spacing"`
+}
+
+// Compute mesh statistics from cell center coordinates
+func meshStatistics(points [][2]float64) MeshStats {
+	stats := MeshStats{}
+	distances := nearestNeighborDistances(points)
+	if len(distances) == 0 {
+		return stats
+	}
+
+	sort.Float64s(distances)
+	var sum float64
+	for _, d := range distances {
+		sum += d
+	}
+	stats.NominalSpacing = sum / float64(len(distances))
+	stats.MinSpacing = distances[0]
+	stats.MaxSpacing = distances[len(distances)-1]
+
+	mid := len(distances) / 2
+	if len(distances)%2 == 0 {
+		stats.MedianSpacing = (distances[mid-1] + distances[mid]) / 2
+	} else {
+		stats.MedianSpacing = distances[mid]
+	}
+	return stats
+}
+
+// Distance of each point to its nearest neighbor.
+// Points are bucketed in a grid so that only neighboring buckets are searched.
+func nearestNeighborDistances(points [][2]float64) []float64 {
+	if len(points) < 2 {
+		return []float64{}
+	}
+
+	minX, minY := points[0][0], points[0][1]
+	maxX, maxY := minX, minY
+	for _, p := range points {
+		minX, maxX = math.Min(minX, p[0]), math.Max(maxX, p[0])
+		minY, maxY = math.Min(minY, p[1]), math.Max(maxY, p[1])
+	}
+
+	// about one point per bucket
+	bucketSize := math.Sqrt((maxX - minX) * (maxY - minY) / float64(len(points)))
+	if bucketSize == 0 || math.IsNaN(bucketSize) {
+		bucketSize = math.Max(maxX-minX, maxY-minY) / float64(len(points))
+	}
+	if bucketSize == 0 {
+		// all points are at the same location
+		return make([]float64, len(points))
+	}
+
+	type bucket struct{ col, row int }
+	grid := make(map[bucket][]int)
+	bucketOf := func(p [2]float64) bucket {
+		return bucket{int((p[0] - minX) / bucketSize), int((p[1] - minY) / bucketSize)}
+	}
+	for n, p := range points {
+		b := bucketOf(p)
+		grid[b] = append(grid[b], n)
+	}
+	maxRing := int(math.Max(maxX-minX, maxY-minY)/bucketSize) + 1
+
+	distances := make([]float64, len(points))
+	for n, p := range points {
+		b := bucketOf(p)
+		best := math.Inf(1)
+		for ring := 0; ring <= maxRing; ring++ {
+			// points in buckets of the next ring are at least ring*bucketSize away
+			if float64(ring-1)*bucketSize > best {
+				break
+			}
+			for col := b.col - ring; col <= b.col+ring; col++ {
+				for row := b.row - ring; row <= b.row+ring; row++ {
+					if ring > 0 && col > b.col-ring && col < b.col+ring && row > b.row-ring && row < b.row+ring {
+						// inner buckets were searched in previous rings
+						continue
+					}
+					for _, other := range grid[bucket{col, row}] {
+						if other == n {
+							continue
+						}
+						d := math.Hypot(points[other][0]-p[0], points[other][1]-p[1])
+						if d < best {
+							best = d
+						}
+					}
+				}
+			}
+		}
+		distances[n] = best
+	}
+	return distances
+}
@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestMeshStatistics(t *testing.T) {
+	grid := [][2]float64{}
+	for x := 0.0; x < 30; x += 10 {
+		for y := 0.0; y < 30; y += 10 {
+			grid = append(grid, [2]float64{x, y})
+		}
+	}
+
+	tests := []struct {
+		name     string
+		points   [][2]float64
+		expected MeshStats
+	}{
+		{"no points", [][2]float64{}, MeshStats{}},
+		{"single point", [][2]float64{{1, 1}}, MeshStats{}},
+		{"two points", [][2]float64{{0, 0}, {3, 4}}, MeshStats{NominalSpacing: 5, MinSpacing: 5, MaxSpacing: 5, MedianSpacing: 5}},
+		{"regular grid", grid, MeshStats{NominalSpacing: 10, MinSpacing: 10, MaxSpacing: 10, MedianSpacing: 10}},
+		{"collinear points", [][2]float64{{0, 0}, {1, 0}, {3, 0}, {7, 0}}, MeshStats{NominalSpacing: 2, MinSpacing: 1, MaxSpacing: 4, MedianSpacing: 1.5}},
+		{"duplicate points", [][2]float64{{2, 2}, {2, 2}, {2, 2}}, MeshStats{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if stats := meshStatistics(tc.points); stats != tc.expected {
+				t.Errorf("got %+v, want %+v", stats, tc.expected)
+			}
+		})
+	}
+}
+
+func TestNearestNeighborDistances(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := make([][2]float64, 500)
+	for n := range points {
+		// denser cluster in a corner so that buckets are unevenly filled
+		scale := 1000.0
+		if n%2 == 0 {
+			scale = 50
+		}
+		points[n] = [2]float64{r.Float64() * scale, r.Float64() * scale}
+	}
+
+	distances := nearestNeighborDistances(points)
+	for n, p := range points {
+		best := math.Inf(1)
+		for m, other := range points {
+			if m != n {
+				best = math.Min(best, math.Hypot(other[0]-p[0], other[1]-p[1]))
+			}
+		}
+		if distances[n] != best {
+			t.Fatalf("point %d: got %v, want %v", n, distances[n], best)
+		}
+	}
+
+	sort.Float64s(distances)
+	if stats := meshStatistics(points); stats.MinSpacing != distances[0] || stats.MaxSpacing != distances[len(distances)-1] {
+		t.Errorf("unexpected spacing range %+v", stats)
+	}
+}
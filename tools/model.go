@@ -223,30 +223,25 @@ func (rm *RasModel) IsGeospatial() bool {
 }
 
 // GeospatialData ...
-func (rm *RasModel) GeospatialData(destinationCRS int) (GeoData, error) {
-	gd := GeoData{}
-	if rm.IsGeospatial() {
-		modelUnits := rm.Metadata.ProjFileContents.Units
+// When opts.SourceCRS is set the model is georeferenced with it, which allows models without a projection
+// or with geometry versions older than 4.0 to be processed.
+func (rm *RasModel) GeospatialData(destinationCRS string, opts GeoOptions) (GeoData, error) {
+	if opts.SourceCRS == "" && !rm.IsGeospatial() {
+		err := errors.New("the model is not geospatial")
+		return GeoData{}, errors.Wrap(err, 0)
+	}
 
-		sourceCRS := rm.Metadata.Projection
+	gd, sourceCRS, err := NewGeoData(rm.Metadata.Projection, rm.Metadata.ProjFileContents.Units, destinationCRS, opts)
+	if err != nil {
+		return gd, errors.Wrap(err, 0)
+	}
 
-		if err := checkUnitConsistency(modelUnits, sourceCRS); err != nil {
+	for _, g := range rm.Metadata.GeomFiles {
+		if err := GetGeospatialData(&gd, rm.FileStore, g.Path, sourceCRS, destinationCRS, opts); err != nil {
 			return gd, errors.Wrap(err, 0)
 		}
-
-		gd.Features = make(map[string]Features)
-		gd.Georeference = destinationCRS
-
-		for _, g := range rm.Metadata.GeomFiles {
-			if err := GetGeospatialData(&gd, rm.FileStore, g.Path, sourceCRS, destinationCRS); err != nil {
-				return gd, errors.Wrap(err, 0)
-			}
-		}
-		return gd, nil
 	}
-	err := errors.New("the model is not geospatial")
-	return gd, errors.Wrap(err, 0)
-
+	return gd, nil
 }
 
 func getModelFiles(rm *RasModel) error {
@@ -357,6 +352,8 @@ func NewRasModel(key string, fs filestore.FileStore) (*RasModel, error) {
 	rasWG.Flow.Wait()
 	rasWG.Projection.Wait()
 
+	attachBreaches(&rm)
+
 	for _, p := range rm.Metadata.PlanFiles {
 		version := p.ProgramVersion
 		if version != "" {
@@ -25,6 +25,8 @@ type PlanFileContents struct {
 	FlowRegime      string //`json:"FlowRegime"`
 	Description     string //`json:"Description"`
 	Notes           string
+
+	Breaches []planBreach `json:"-"` // attached to the structures of the geometry file
 }
 
 // getPlanData Reads a plan file. returns none to allow concurrency
@@ -77,6 +79,13 @@ func getPlanData(rm *RasModel, fn string, wg *sync.WaitGroup) {
 		if match {
 			data := strings.Split(line, "=")
 
+			if strings.HasPrefix(line, "Breach ") {
+				meta.Breaches, err = addPlanBreachLine(meta.Breaches, line, meta.FileExt)
+				if err != nil {
+					log.Println("Breach|", meta.FileExt, err)
+				}
+			}
+
 			switch data[0] {
 
 			case "Plan Title":
@@ -167,3 +167,13 @@ func getPrjData(rm *RasModel) error {
 	rm.Metadata.ProjFileContents = meta
 	return nil
 }
+
+// ProjectUnits returns the unit system of a RAS project file
+func ProjectUnits(fs filestore.FileStore, prjFile string) (string, error) {
+	rm := RasModel{FileStore: fs}
+	rm.Metadata.ProjFilePath = prjFile
+	if err := getPrjData(&rm); err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	return rm.Metadata.ProjFileContents.Units, nil
+}
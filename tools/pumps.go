@@ -0,0 +1,168 @@
+package tools
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-errors/errors" // warning: replaces standard errors
+)
+
+// Store HEC-RAS Pump Stations
+type PumpStation struct {
+	Description string
+	X           float64
+	Y           float64
+	From        pumpLocation
+	To          pumpLocation
+	NumGroups   int          `json:"Num Pump Groups"`
+	Groups      []pumpGroups `json:"Pump Groups"`
+}
+
+// Source or destination of a pump station, either a reach river station, a storage area or a 2D area
+type pumpLocation struct {
+	Reach       string `json:",omitempty"`
+	RS          string `json:",omitempty"`
+	StorageArea string `json:"Storage Area,omitempty"`
+	TwoDArea    string `json:"2D Area,omitempty"`
+}
+
+// Group of identical pumps sharing a head-flow curve
+type pumpGroups struct {
+	Name          string
+	NumPumps      int          `json:"Num Pumps"`
+	OnElevations  []float64    `json:"On Elevations"`  // one trigger elevation per pump
+	OffElevations []float64    `json:"Off Elevations"` // one trigger elevation per pump
+	HeadFlow      [][2]float64 `json:"Head Flow Curve"`
+}
+
+// Extract Pump Station data from a Pump Station text block.
+// Returns when a new geometry element is encountered, in which case the next scan must be skipped.
+func getPumpStation(sc *bufio.Scanner) (string, PumpStation, bool, error) {
+	pump := PumpStation{}
+	lineData := strings.Split(rightofEquals(sc.Text()), ",")
+	name := strings.TrimSpace(lineData[0])
+
+	if len(lineData) > 2 {
+		x, err := stringtoFloat(lineData[1])
+		if err != nil {
+			return name, pump, false, errors.Wrap(err, 0)
+		}
+		y, err := stringtoFloat(lineData[2])
+		if err != nil {
+			return name, pump, false, errors.Wrap(err, 0)
+		}
+		pump.X, pump.Y = x, y
+	}
+
+	for sc.Scan() {
+		line := sc.Text()
+		if stringInSlice(leftofEquals(line), geomElementsPrefix[:]) {
+			// a new HEC RAS element has been encountered, skip next scan and return
+			return name, pump, true, nil
+		}
+
+		switch {
+		case strings.HasPrefix(line, "Pump Station Desc="):
+			pump.Description = strings.Trim(rightofEquals(line), ", ")
+
+		case strings.HasPrefix(line, "Pump From="):
+			pump.From = getPumpLocation(line)
+
+		case strings.HasPrefix(line, "Pump To="):
+			pump.To = getPumpLocation(line)
+
+		case strings.HasPrefix(line, "Pump Group="):
+			groupData := strings.Split(rightofEquals(line), ",")
+			group := pumpGroups{Name: strings.TrimSpace(groupData[0])}
+			if len(groupData) > 1 && strings.TrimSpace(groupData[1]) != "" {
+				numPumps, err := strconv.Atoi(strings.TrimSpace(groupData[1]))
+				if err != nil {
+					return name, pump, false, errors.Wrap(err, 0)
+				}
+				group.NumPumps = numPumps
+			}
+			pump.Groups = append(pump.Groups, group)
+			pump.NumGroups++
+
+		case strings.HasPrefix(line, "Pump Group On Off="):
+			if len(pump.Groups) == 0 {
+				return name, pump, false, errors.Errorf("Pump trigger elevations found before any pump group at line '%s'", line)
+			}
+			group := &pump.Groups[len(pump.Groups)-1]
+			// values alternate between on and off elevation of each pump
+			onOff := strings.Split(rightofEquals(line), ",")
+			for n := 0; n+1 < len(onOff); n += 2 {
+				on, err := stringtoFloat(onOff[n])
+				if err != nil {
+					return name, pump, false, errors.Wrap(err, 0)
+				}
+				off, err := stringtoFloat(onOff[n+1])
+				if err != nil {
+					return name, pump, false, errors.Wrap(err, 0)
+				}
+				group.OnElevations = append(group.OnElevations, on)
+				group.OffElevations = append(group.OffElevations, off)
+			}
+
+		case strings.HasPrefix(line, "#Pump Group HQ="):
+			if len(pump.Groups) == 0 {
+				return name, pump, false, errors.Errorf("Pump head-flow curve found before any pump group at line '%s'", line)
+			}
+			nPairs, err := strconv.Atoi(rightofEquals(line))
+			if err != nil {
+				return name, pump, false, errors.Wrap(err, 0)
+			}
+			if nPairs == 0 {
+				continue
+			}
+			pairs, err := dataPairsfromTextBlock(sc, nPairs, 80, 8)
+			if err != nil {
+				return name, pump, false, errors.Wrap(err, 0)
+			}
+			pump.Groups[len(pump.Groups)-1].HeadFlow = pairs
+		}
+	}
+	return name, pump, false, nil
+}
+
+// Pump location lines are formatted as river, reach, river station, area
+func getPumpLocation(line string) pumpLocation {
+	location := pumpLocation{}
+	locData := strings.Split(rightofEquals(line), ",")
+	for len(locData) < 4 {
+		locData = append(locData, "")
+	}
+	river, reach := strings.TrimSpace(locData[0]), strings.TrimSpace(locData[1])
+	if river != "" {
+		location.Reach = fmt.Sprintf("%s, %s", river, reach)
+		location.RS = strings.TrimSpace(locData[2])
+	}
+	location.StorageArea = strings.TrimSpace(locData[3])
+	return location
+}
+
+// Extract Pump Stations Data
+func getPumpStationsData(rm *RasModel, fn string, i int) (string, PumpStation, error) {
+	f, err := rm.FileStore.GetObject(fn)
+	if err != nil {
+		return "", PumpStation{}, errors.Wrap(err, 0)
+	}
+	defer f.Close()
+
+	pSc := bufio.NewScanner(f)
+
+	pi := 0
+	for pSc.Scan() {
+		pi++
+		if pi == i {
+			name, pump, _, err := getPumpStation(pSc)
+			if err != nil {
+				return name, pump, errors.Wrap(err, 0)
+			}
+			return name, pump, nil
+		}
+	}
+	return "", PumpStation{}, errors.New(fmt.Sprintf("Failed to parse pump station at geom file line number %v of %v", i, fn))
+}
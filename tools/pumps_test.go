@@ -0,0 +1,68 @@
+package tools
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGetPumpStation(t *testing.T) {
+	text := strings.Join([]string{
+		"Pump Station=Lift Station,1000.5,2000",
+		"Pump Station Desc=Drains the pond, ",
+		"Pump From=,,,Pond",
+		"Pump To=Creek,Lower,50",
+		"Pump Group=Group 1,2",
+		"Pump Group On Off=101,100,102,100.5",
+		"#Pump Group HQ=2",
+		"       5      20      10      15",
+		"Pump Group=Group 2,",
+		"Storage Area=Pond,1000,2000",
+	}, "\n")
+	sc := bufio.NewScanner(strings.NewReader(text))
+	sc.Scan()
+	name, pump, skipScan, err := getPumpStation(sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := PumpStation{
+		Description: "Drains the pond", X: 1000.5, Y: 2000,
+		From:      pumpLocation{StorageArea: "Pond"},
+		To:        pumpLocation{Reach: "Creek, Lower", RS: "50"},
+		NumGroups: 2,
+		Groups: []pumpGroups{
+			{Name: "Group 1", NumPumps: 2, OnElevations: []float64{101, 102}, OffElevations: []float64{100, 100.5}, HeadFlow: [][2]float64{{5, 20}, {10, 15}}},
+			{Name: "Group 2"},
+		},
+	}
+	if name != "Lift Station" || !reflect.DeepEqual(pump, expected) {
+		t.Errorf("got %q %+v, want %q %+v", name, pump, "Lift Station", expected)
+	}
+	if !skipScan || sc.Text() != "Storage Area=Pond,1000,2000" {
+		t.Errorf("expected the next element to be left to the caller, scanner at %q", sc.Text())
+	}
+
+	sc = bufio.NewScanner(strings.NewReader("Pump Station=Orphan\nPump Group On Off=101,100"))
+	sc.Scan()
+	if _, _, _, err := getPumpStation(sc); err == nil {
+		t.Error("expected an error for trigger elevations without a pump group")
+	}
+}
+
+func TestGetPumpLocation(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected pumpLocation
+	}{
+		{"Pump From=Creek,Lower,50,", pumpLocation{Reach: "Creek, Lower", RS: "50"}},
+		{"Pump To=,,,Pond", pumpLocation{StorageArea: "Pond"}},
+		{"Pump To=", pumpLocation{}},
+	}
+	for _, tc := range tests {
+		if location := getPumpLocation(tc.line); location != tc.expected {
+			t.Errorf("%s: got %+v, want %+v", tc.line, location, tc.expected)
+		}
+	}
+}
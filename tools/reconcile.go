@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"math"
+)
+
+// Methods to reconcile a cross section profile with a cut line of a different length
+const (
+	ReconcileNone    = "none"    // keep the cut line without elevations
+	ReconcileStretch = "stretch" // scale profile stations to the cut line length
+	ReconcileClip    = "clip"    // trim the longer of the profile and the cut line
+	ReconcileExtend  = "extend"  // extend the shorter of the profile and the cut line
+)
+
+// Default tolerance between the profile and cut line lengths, in model units
+const defaultReconcileTolerance float64 = 0.1
+
+// Store how the profile of a cross section was fitted to its cut line
+type xsReconciliation struct {
+	Method        string
+	MismatchRatio float64 // profile length over cut line length
+	Match         bool    // profile and cut line lengths agree within tolerance
+}
+
+// The profile can be draped on the cut line
+func (r xsReconciliation) hasProfile() bool {
+	return r.Match || (r.Method != "" && r.Method != ReconcileNone)
+}
+
+// ValidReconcileMethod checks that a cut line reconciliation method is known
+func ValidReconcileMethod(method string) bool {
+	switch method {
+	case "", ReconcileNone, ReconcileStretch, ReconcileClip, ReconcileExtend:
+		return true
+	}
+	return false
+}
+
+// Fit the profile of a cross section to its cut line. Lengths within tolerance are left unchanged,
+// otherwise the profile or the cut line is modified according to the method of the options.
+// Bank and levee stations are moved along with the profile.
+func reconcileXS(xs crossSections, opts GeoOptions) (crossSections, xsReconciliation) {
+	rec := xsReconciliation{}
+	if len(xs.CutLine) < 2 || len(xs.StationElevation) < 2 {
+		return xs, rec
+	}
+
+	cutLineDistances := cumulativeDistances(xs.CutLine)
+	lenCutLine := cutLineDistances[len(cutLineDistances)-1]
+	startingStation := xs.StationElevation[0][0]
+	lenProfile := xs.StationElevation[len(xs.StationElevation)-1][0] - startingStation
+	if lenCutLine > 0 {
+		rec.MismatchRatio = lenProfile / lenCutLine
+	}
+
+	tolerance := opts.ReconcileTolerance
+	if tolerance <= 0 {
+		tolerance = defaultReconcileTolerance
+	}
+	if math.Abs(lenProfile-lenCutLine) <= tolerance {
+		rec.Match = true
+		return xs, rec
+	}
+
+	rec.Method = opts.Reconcile
+	if rec.Method == "" {
+		rec.Method = ReconcileStretch
+	}
+	if lenCutLine == 0 || lenProfile <= 0 {
+		rec.Method = ReconcileNone
+	}
+
+	switch rec.Method {
+	case ReconcileStretch:
+		factor := lenCutLine / lenProfile
+		xs = mapXSStations(xs, func(station float64) float64 {
+			return startingStation + (station-startingStation)*factor
+		})
+
+	case ReconcileClip:
+		if lenProfile > lenCutLine {
+			endingStation := startingStation + lenCutLine
+			xs.StationElevation = clipProfile(xs.StationElevation, endingStation)
+			xs = mapXSStations(xs, func(station float64) float64 {
+				return math.Min(station, endingStation)
+			})
+		} else {
+			xs.CutLine = subPolyline(xs.CutLine, 0, lenProfile)
+		}
+
+	case ReconcileExtend:
+		if lenProfile > lenCutLine {
+			n := len(xs.CutLine)
+			p0, p1 := xs.CutLine[n-2], xs.CutLine[n-1]
+			segLength := math.Hypot(p1[0]-p0[0], p1[1]-p0[1])
+			if segLength == 0 {
+				rec.Method = ReconcileNone
+				break
+			}
+			extension := (lenProfile - lenCutLine) / segLength
+			cutLine := append([][2]float64{}, xs.CutLine...)
+			xs.CutLine = append(cutLine, [2]float64{p1[0] + (p1[0]-p0[0])*extension, p1[1] + (p1[1]-p0[1])*extension})
+		} else {
+			lastElevation := xs.StationElevation[len(xs.StationElevation)-1][1]
+			profile := append([][2]float64{}, xs.StationElevation...)
+			xs.StationElevation = append(profile, [2]float64{startingStation + lenCutLine, lastElevation})
+		}
+	}
+	return xs, rec
+}
+
+// Apply a function to the profile, bank and levee stations of a cross section
+func mapXSStations(xs crossSections, f func(float64) float64) crossSections {
+	profile := make([][2]float64, len(xs.StationElevation))
+	for n, pair := range xs.StationElevation {
+		profile[n] = [2]float64{f(pair[0]), pair[1]}
+	}
+	xs.StationElevation = profile
+	xs.LeftBank, xs.RightBank = f(xs.LeftBank), f(xs.RightBank)
+
+	if xs.LeftLevee != nil {
+		xs.LeftLevee = &levees{Station: f(xs.LeftLevee.Station), Elevation: xs.LeftLevee.Elevation}
+	}
+	if xs.RightLevee != nil {
+		xs.RightLevee = &levees{Station: f(xs.RightLevee.Station), Elevation: xs.RightLevee.Elevation}
+	}
+	return xs
+}
+
+// Drop the profile points beyond a station, the elevation at that station is interpolated unless a point lies on it
+func clipProfile(profile [][2]float64, endingStation float64) [][2]float64 {
+	clipped := [][2]float64{}
+	for n, pair := range profile {
+		if pair[0] <= endingStation {
+			clipped = append(clipped, pair)
+			continue
+		}
+		prev := profile[n-1]
+		if prev[0] == endingStation {
+			return clipped
+		}
+		elevation := prev[1]
+		if pair[0] != prev[0] {
+			elevation += (endingStation - prev[0]) / (pair[0] - prev[0]) * (pair[1] - prev[1])
+		}
+		return append(clipped, [2]float64{endingStation, elevation})
+	}
+	return clipped
+}
@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dewberry/gdal"
+)
+
+func TestReconcileXS(t *testing.T) {
+	cutLine := [][2]float64{{0, 0}, {100, 0}}
+	long := crossSections{
+		CutLine:          cutLine,
+		StationElevation: [][2]float64{{0, 10}, {100, 5}, {200, 10}},
+		LeftBank:         40,
+		RightBank:        160,
+		RightLevee:       &levees{Station: 180, Elevation: 12},
+	}
+	short := crossSections{
+		CutLine:          cutLine,
+		StationElevation: [][2]float64{{0, 10}, {50, 5}},
+		LeftBank:         10,
+		RightBank:        40,
+	}
+	matching := crossSections{CutLine: cutLine, StationElevation: [][2]float64{{0, 10}, {100.05, 10}}}
+	noCutLine := crossSections{CutLine: [][2]float64{{0, 0}, {0, 0}}, StationElevation: long.StationElevation}
+
+	tests := []struct {
+		name     string
+		xs       crossSections
+		opts     GeoOptions
+		expected crossSections
+		rec      xsReconciliation
+	}{
+		{"within tolerance", matching, GeoOptions{Reconcile: ReconcileStretch}, matching, xsReconciliation{MismatchRatio: 1.0005, Match: true}},
+		{"outside a custom tolerance", matching, GeoOptions{Reconcile: ReconcileNone, ReconcileTolerance: 0.01}, matching, xsReconciliation{Method: ReconcileNone, MismatchRatio: 1.0005}},
+		{"none on request", long, GeoOptions{Reconcile: ReconcileNone}, long, xsReconciliation{Method: ReconcileNone, MismatchRatio: 2}},
+		{
+			"stretch by default", long, GeoOptions{},
+			crossSections{
+				CutLine: cutLine, StationElevation: [][2]float64{{0, 10}, {50, 5}, {100, 10}}, LeftBank: 20, RightBank: 80,
+				RightLevee: &levees{Station: 90, Elevation: 12},
+			},
+			xsReconciliation{Method: ReconcileStretch, MismatchRatio: 2},
+		},
+		{
+			"clip a longer profile", long, GeoOptions{Reconcile: ReconcileClip},
+			crossSections{
+				CutLine: cutLine, StationElevation: [][2]float64{{0, 10}, {100, 5}}, LeftBank: 40, RightBank: 100,
+				RightLevee: &levees{Station: 100, Elevation: 12},
+			},
+			xsReconciliation{Method: ReconcileClip, MismatchRatio: 2},
+		},
+		{
+			"clip a longer cut line", short, GeoOptions{Reconcile: ReconcileClip},
+			crossSections{CutLine: [][2]float64{{0, 0}, {50, 0}}, StationElevation: short.StationElevation, LeftBank: 10, RightBank: 40},
+			xsReconciliation{Method: ReconcileClip, MismatchRatio: 0.5},
+		},
+		{
+			"extend a shorter cut line", long, GeoOptions{Reconcile: ReconcileExtend},
+			crossSections{
+				CutLine: [][2]float64{{0, 0}, {100, 0}, {200, 0}}, StationElevation: long.StationElevation, LeftBank: 40, RightBank: 160,
+				RightLevee: long.RightLevee,
+			},
+			xsReconciliation{Method: ReconcileExtend, MismatchRatio: 2},
+		},
+		{
+			"extend a shorter profile", short, GeoOptions{Reconcile: ReconcileExtend},
+			crossSections{CutLine: cutLine, StationElevation: [][2]float64{{0, 10}, {50, 5}, {100, 5}}, LeftBank: 10, RightBank: 40},
+			xsReconciliation{Method: ReconcileExtend, MismatchRatio: 0.5},
+		},
+		{"cut line without length", noCutLine, GeoOptions{Reconcile: ReconcileStretch}, noCutLine, xsReconciliation{Method: ReconcileNone}},
+		{"no profile", crossSections{CutLine: cutLine}, GeoOptions{Reconcile: ReconcileStretch}, crossSections{CutLine: cutLine}, xsReconciliation{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			xs, rec := reconcileXS(tc.xs, tc.opts)
+			if !reflect.DeepEqual(xs, tc.expected) {
+				t.Errorf("got %+v, want %+v", xs, tc.expected)
+			}
+			if rec != tc.rec {
+				t.Errorf("got reconciliation %+v, want %+v", rec, tc.rec)
+			}
+		})
+	}
+}
+
+func TestGetXSBanksDefaultReconcile(t *testing.T) {
+	text := strings.Join([]string{
+		"Type RM Length L Ch R = 1 ,200     ,100,100,100",
+		"XS GIS Cut Line=2",
+		"            1000            2000            1100            2000",
+		"#Sta/Elev= 3 ",
+		"       0     110     100     100     200     110",
+		"Bank Sta=40,160",
+		"Type RM Length L Ch R = 1 ,100     ,100,100,100",
+	}, "\n")
+	sc := bufio.NewScanner(strings.NewReader(text))
+	sc.Scan()
+
+	xsFeature, banks, _, _, err := getXSBanks(sc, identityTransform(t), "Creek, Upper", GeoOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xsFeature.Fields["ReconcileMethod"] != ReconcileStretch {
+		t.Errorf("got method %v, want %q", xsFeature.Fields["ReconcileMethod"], ReconcileStretch)
+	}
+
+	geom, err := gdal.CreateFromWKB(xsFeature.Geometry, gdal.SpatialReference{}, len(xsFeature.Geometry))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer geom.Destroy()
+	lineString := geom.Geometry(0)
+	points := [][3]float64{}
+	for i := 0; i < lineString.PointCount(); i++ {
+		x, y, z := lineString.Point(i)
+		points = append(points, [3]float64{x, y, z})
+	}
+	expected := [][3]float64{{1000, 2000, 110}, {1050, 2000, 100}, {1100, 2000, 110}}
+	if !reflect.DeepEqual(points, expected) {
+		t.Errorf("got cut line points %v, want %v", points, expected)
+	}
+
+	// banks keep the stations written in the geometry file as names
+	if len(banks) != 2 || banks[0].FeatureName != "40" || banks[1].FeatureName != "160" {
+		t.Fatalf("got banks %+v, want banks 40 and 160", banks)
+	}
+	for n, x := range []float64{1020, 1080} {
+		bank, err := gdal.CreateFromWKB(banks[n].Geometry, gdal.SpatialReference{}, len(banks[n].Geometry))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotX, y, _ := bank.Geometry(0).Point(0)
+		if gotX != x || y != 2000 {
+			t.Errorf("bank %s: got (%v, %v), want (%v, 2000)", banks[n].FeatureName, gotX, y, x)
+		}
+		bank.Destroy()
+	}
+}
@@ -0,0 +1,75 @@
+package tools
+
+import (
+	"math"
+
+	"github.com/dewberry/gdal"
+	"github.com/go-errors/errors" // warning: replaces standard errors
+)
+
+// VertexCount is the number of vertices of a layer before and after simplification
+type VertexCount struct {
+	Before int
+	After  int
+}
+
+// Simplify a WKB geometry preserving its topology and round its x and y coordinates to a number of decimals.
+// A tolerance of 0 skips the simplification and a nil precision skips the rounding.
+func simplifyWKB(wkb []uint8, tolerance float64, precision *int) ([]uint8, int, int, error) {
+	if len(wkb) == 0 {
+		return wkb, 0, 0, nil
+	}
+	geom, err := gdal.CreateFromWKB(wkb, gdal.SpatialReference{}, len(wkb))
+	if err != nil {
+		return wkb, 0, 0, errors.Wrap(err, 0)
+	}
+	defer geom.Destroy()
+	before := vertexCount(geom)
+
+	if tolerance > 0 {
+		simplified := geom.SimplifyPreservingTopology(tolerance)
+		if simplified == (gdal.Geometry{}) {
+			return wkb, before, before, errors.New("failed to simplify geometry")
+		}
+		defer simplified.Destroy()
+		geom = simplified
+	}
+
+	if precision != nil {
+		roundXY(geom, *precision)
+	}
+
+	simplifiedWKB, err := geom.ToWKB()
+	if err != nil {
+		return wkb, before, before, errors.Wrap(err, 0)
+	}
+	return simplifiedWKB, before, vertexCount(geom), nil
+}
+
+// Number of vertices of a geometry and of its sub geometries
+func vertexCount(geom gdal.Geometry) int {
+	count := geom.PointCount()
+	for i := 0; i < geom.GeometryCount(); i++ {
+		count += vertexCount(geom.Geometry(i))
+	}
+	return count
+}
+
+// Round the x and y coordinates of a geometry and of its sub geometries in place
+func roundXY(geom gdal.Geometry, precision int) {
+	for i := 0; i < geom.GeometryCount(); i++ {
+		roundXY(geom.Geometry(i), precision)
+	}
+
+	scale := math.Pow(10, float64(precision))
+	is3D := geom.CoordinateDimension() == 3
+	for i := 0; i < geom.PointCount(); i++ {
+		x, y, z := geom.Point(i)
+		x, y = math.Round(x*scale)/scale, math.Round(y*scale)/scale
+		if is3D {
+			geom.SetPoint(i, x, y, z)
+		} else {
+			geom.SetPoint2D(i, x, y)
+		}
+	}
+}
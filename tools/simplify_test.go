@@ -0,0 +1,83 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dewberry/gdal"
+)
+
+// x, y and z values of the line strings of a multi line string WKB
+func wkbLinePoints(t *testing.T, wkb []uint8) [][][3]float64 {
+	geom, err := gdal.CreateFromWKB(wkb, gdal.SpatialReference{}, len(wkb))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer geom.Destroy()
+
+	lines := [][][3]float64{}
+	for i := 0; i < geom.GeometryCount(); i++ {
+		line := [][3]float64{}
+		lineString := geom.Geometry(i)
+		for j := 0; j < lineString.PointCount(); j++ {
+			x, y, z := lineString.Point(j)
+			line = append(line, [3]float64{x, y, z})
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestSimplifyWKB(t *testing.T) {
+	river := gdal.Create(gdal.GT_LineString)
+	for _, pair := range [][2]float64{{0, 0}, {1, 0.001}, {2, 0}, {3, 5}} {
+		river.AddPoint2D(pair[0], pair[1])
+	}
+	riverWKB, err := river.ForceToMultiLineString().ToWKB()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	xs := gdal.Create(gdal.GT_LineString25D)
+	xs.AddPoint(1.234, 2.345, 10.555)
+	xs.AddPoint(5.678, 2.345, 11.5)
+	xsWKB, err := xs.ForceToMultiLineString().ToWKB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	one := 1
+
+	tests := []struct {
+		name      string
+		wkb       []uint8
+		tolerance float64
+		precision *int
+		before    int
+		after     int
+		expected  [][][3]float64
+	}{
+		{"simplify", riverWKB, 0.01, nil, 4, 3, [][][3]float64{{{0, 0, 0}, {2, 0, 0}, {3, 5, 0}}}},
+		{"tolerance below the deviation", riverWKB, 0.0001, nil, 4, 4, [][][3]float64{{{0, 0, 0}, {1, 0.001, 0}, {2, 0, 0}, {3, 5, 0}}}},
+		{"round x and y", riverWKB, 0, &one, 4, 4, [][][3]float64{{{0, 0, 0}, {1, 0, 0}, {2, 0, 0}, {3, 5, 0}}}},
+		{"round x and y keeping z", xsWKB, 0, &one, 2, 2, [][][3]float64{{{1.2, 2.3, 10.555}, {5.7, 2.3, 11.5}}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			wkb, before, after, err := simplifyWKB(tc.wkb, tc.tolerance, tc.precision)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if before != tc.before || after != tc.after {
+				t.Errorf("got %d vertices before and %d after, want %d and %d", before, after, tc.before, tc.after)
+			}
+			if got := wkbLinePoints(t, wkb); !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("got %v, want %v", got, tc.expected)
+			}
+		})
+	}
+
+	wkb, before, after, err := simplifyWKB(nil, 0.01, &one)
+	if err != nil || len(wkb) != 0 || before != 0 || after != 0 {
+		t.Errorf("got (%v, %d, %d, %v) for a feature without geometry, want no vertices", wkb, before, after, err)
+	}
+}
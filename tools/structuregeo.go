@@ -0,0 +1,325 @@
+package tools
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/USACE/filestore"
+	"github.com/dewberry/gdal"
+	"github.com/go-errors/errors" // warning: replaces standard errors
+)
+
+// Map of HEC RAS node type to Hydraulic Structure Types
+var structureTypes map[int]string = map[int]string{
+	2: "Culvert",
+	3: "Bridge",
+	5: "Inline Structure",
+	6: "Lateral Structure"}
+
+// Store what is needed to locate a hydraulic structure along its reach
+type structureLocation struct {
+	Type       string
+	Name       string
+	RS         string
+	Station    float64
+	CutLine    [][2]float64
+	Distance   float64 // lateral structures only, distance from the upstream cross-section
+	WeirLength float64 // lateral structures only
+}
+
+// River station of a cross-section and distance along the centerline where its cut line crosses the centerline
+type xsCrossing struct {
+	Station      float64
+	Distance     float64
+	CutLineWidth float64
+}
+
+// Extract location data of a hydraulic structure.
+// Returns when a new geometry element is encountered, in which case the next scan must be skipped.
+func getStructureLocation(sc *bufio.Scanner, structureType string, lineData []string) (structureLocation, bool, error) {
+	location := structureLocation{Type: structureType, RS: strings.TrimSpace(lineData[1])}
+
+	station, err := parseFloat(strings.TrimSpace(lineData[1]), 64)
+	if err != nil {
+		return location, false, errors.Wrap(err, 0)
+	}
+	location.Station = station
+
+	for sc.Scan() {
+		line := sc.Text()
+		if stringInSlice(leftofEquals(line), geomElementsPrefix[:]) {
+			// a new HEC RAS element has been encountered, skip next scan and return
+			return location, true, nil
+		}
+
+		switch {
+		case strings.HasPrefix(line, "Node Name="):
+			location.Name = rightofEquals(line)
+
+		case strings.HasPrefix(line, "XS GIS Cut Line="):
+			location.CutLine, err = polylineFromTextBlock(sc, line)
+
+		case strings.HasPrefix(line, "Lateral Weir Distance="):
+			location.Distance, err = stringtoFloat(rightofEquals(line))
+
+		case strings.HasPrefix(line, "#Lateral Weir SE="):
+			var nPairs int
+			nPairs, err = atoiOrZero(rightofEquals(line))
+			if err != nil || nPairs == 0 {
+				break
+			}
+			var pairs [][2]float64
+			pairs, err = dataPairsfromTextBlock(sc, nPairs, 80, 8)
+			if len(pairs) > 0 {
+				location.WeirLength = pairs[len(pairs)-1][0] - pairs[0][0]
+			}
+		}
+		if err != nil {
+			return location, false, errors.Wrap(err, 0)
+		}
+	}
+	return location, false, nil
+}
+
+// Cumulative distance along a polyline at each of its vertices
+func cumulativeDistances(polyline [][2]float64) []float64 {
+	distances := make([]float64, len(polyline))
+	for n := 1; n < len(polyline); n++ {
+		distances[n] = distances[n-1] + math.Hypot(polyline[n][0]-polyline[n-1][0], polyline[n][1]-polyline[n-1][1])
+	}
+	return distances
+}
+
+// Distance along a polyline where another polyline first crosses it
+func crossingDistance(polyline [][2]float64, other [][2]float64) (float64, bool) {
+	distances := cumulativeDistances(polyline)
+	for n := 1; n < len(polyline); n++ {
+		p, r := polyline[n-1], [2]float64{polyline[n][0] - polyline[n-1][0], polyline[n][1] - polyline[n-1][1]}
+		for m := 1; m < len(other); m++ {
+			q, s := other[m-1], [2]float64{other[m][0] - other[m-1][0], other[m][1] - other[m-1][1]}
+			denom := r[0]*s[1] - r[1]*s[0]
+			if denom == 0 {
+				continue
+			}
+			t := ((q[0]-p[0])*s[1] - (q[1]-p[1])*s[0]) / denom
+			u := ((q[0]-p[0])*r[1] - (q[1]-p[1])*r[0]) / denom
+			if t >= 0 && t <= 1 && u >= 0 && u <= 1 {
+				return distances[n-1] + t*(distances[n]-distances[n-1]), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// Point and unit tangent at a distance along a polyline
+func pointAlong(polyline [][2]float64, distance float64) ([2]float64, [2]float64) {
+	distances := cumulativeDistances(polyline)
+	for n := 1; n < len(polyline); n++ {
+		segLength := distances[n] - distances[n-1]
+		if (distance <= distances[n] || n == len(polyline)-1) && segLength > 0 {
+			t := (distance - distances[n-1]) / segLength
+			t = math.Max(0, math.Min(1, t))
+			tangent := [2]float64{(polyline[n][0] - polyline[n-1][0]) / segLength, (polyline[n][1] - polyline[n-1][1]) / segLength}
+			return [2]float64{polyline[n-1][0] + t*(polyline[n][0]-polyline[n-1][0]), polyline[n-1][1] + t*(polyline[n][1]-polyline[n-1][1])}, tangent
+		}
+	}
+	return polyline[0], [2]float64{}
+}
+
+// Part of a polyline between two distances along it
+func subPolyline(polyline [][2]float64, start float64, end float64) [][2]float64 {
+	distances := cumulativeDistances(polyline)
+	startPoint, _ := pointAlong(polyline, start)
+	endPoint, _ := pointAlong(polyline, end)
+
+	sub := [][2]float64{startPoint}
+	for n, d := range distances {
+		if d > start && d < end {
+			sub = append(sub, polyline[n])
+		}
+	}
+	return append(sub, endPoint)
+}
+
+// Locate a structure on its reach. Structures with their own cut line use it, otherwise the structure is placed
+// on the centerline by interpolating its river station between the crossings of the bracketing cross-sections.
+// Lateral structures follow the centerline, other structures are drawn across it.
+func locateStructure(location structureLocation, centerline [][2]float64, crossings []xsCrossing) ([][2]float64, string, error) {
+	if len(location.CutLine) >= 2 {
+		return location.CutLine, "Cut Line", nil
+	}
+	if len(centerline) < 2 {
+		return nil, "", errors.New("the river centerline could not be extracted")
+	}
+
+	// crossings are sorted from upstream to downstream
+	for n := 1; n < len(crossings); n++ {
+		up, dn := crossings[n-1], crossings[n]
+		if location.Station > up.Station || location.Station < dn.Station {
+			continue
+		}
+
+		if location.Type == "Lateral Structure" {
+			start := up.Distance + location.Distance
+			return subPolyline(centerline, start, start+location.WeirLength), "River Station", nil
+		}
+
+		distance := up.Distance
+		if up.Station != dn.Station {
+			distance += (up.Station - location.Station) / (up.Station - dn.Station) * (dn.Distance - up.Distance)
+		}
+		point, tangent := pointAlong(centerline, distance)
+		halfWidth := (up.CutLineWidth + dn.CutLineWidth) / 4
+		normal := [2]float64{-tangent[1], tangent[0]}
+		return [][2]float64{
+			{point[0] + normal[0]*halfWidth, point[1] + normal[1]*halfWidth},
+			{point[0] - normal[0]*halfWidth, point[1] - normal[1]*halfWidth},
+		}, "River Station", nil
+	}
+	return nil, "", errors.Errorf("river station %s is not between two cross-sections crossing the centerline", location.RS)
+}
+
+// Create the features of the hydraulic structures of a reach
+func getReachStructures(reach reachGeometry, transform gisTransform) ([]VectorFeature, error) {
+	features := []VectorFeature{}
+
+	crossings := []xsCrossing{}
+	for _, xs := range reach.XS {
+		if d, ok := crossingDistance(reach.Centerline, xs.CutLine); ok {
+			cutLineDistances := cumulativeDistances(xs.CutLine)
+			crossings = append(crossings, xsCrossing{Station: xs.Station, Distance: d, CutLineWidth: cutLineDistances[len(cutLineDistances)-1]})
+		}
+	}
+	sort.Slice(crossings, func(i, j int) bool { return crossings[i].Station > crossings[j].Station })
+
+	for _, location := range reach.Structures {
+		feature := VectorFeature{FeatureName: fmt.Sprintf("%s, %s", reach.Name, location.RS), Fields: map[string]interface{}{}}
+		feature.Fields["RiverReachName"] = reach.Name
+		feature.Fields["Type"] = location.Type
+		feature.Fields["Name"] = location.Name
+		feature.Fields["RS"] = location.RS
+
+		xyPairs, locatedBy, err := locateStructure(location, reach.Centerline, crossings)
+		if err != nil {
+			log.Println("Skipped", location.Type, feature.FeatureName, err.Error())
+			continue
+		}
+		feature.Fields["LocatedBy"] = locatedBy
+
+		wkb, err := multiLineStringWKB(xyPairs, transform)
+		if err != nil {
+			return features, errors.Wrap(err, 0)
+		}
+		feature.Geometry = wkb
+		features = append(features, feature)
+	}
+	return features, nil
+}
+
+// Transform a polyline and return it as a MultiLineString WKB
+func multiLineStringWKB(xyPairs [][2]float64, transform gisTransform) ([]uint8, error) {
+	xyLineString := gdal.Create(gdal.GT_LineString)
+	for _, pair := range xyPairs {
+		xyLineString.AddPoint2D(pair[0], pair[1])
+	}
+
+	transform.apply(xyLineString)
+
+	multiLineString := xyLineString.ForceToMultiLineString()
+	wkb, err := multiLineString.ToWKB()
+	if err != nil {
+		return wkb, errors.Wrap(err, 0)
+	}
+	return wkb, nil
+}
+
+// Store the centerline, cross-sections and hydraulic structures of a reach
+type reachGeometry struct {
+	Name       string
+	Centerline [][2]float64
+	XS         []crossSections
+	Structures []structureLocation
+}
+
+// Keep only what locates structures and bank lines: the station, cut line, banks and extent of the profile
+func xsOutline(xs crossSections) crossSections {
+	outline := crossSections{Station: xs.Station, CutLine: xs.CutLine, LeftBank: xs.LeftBank, RightBank: xs.RightBank, BankStations: xs.BankStations}
+	if n := len(xs.StationElevation); n > 0 {
+		outline.StationElevation = [][2]float64{xs.StationElevation[0], xs.StationElevation[n-1]}
+	}
+	return outline
+}
+
+// Extract the centerline, cross-section outlines and hydraulic structure locations of each reach of a geometry file.
+// Each reach is passed to emit once it has been read, so that only one reach is held in memory at a time.
+func getReachGeometries(fs filestore.FileStore, geomFilePath string, emit func(reach reachGeometry) error) error {
+	file, err := fs.GetObject(geomFilePath)
+	if err != nil {
+		return errors.Wrap(err, 0)
+	}
+	defer file.Close()
+
+	sc := bufio.NewScanner(file)
+	var reach *reachGeometry
+
+	eof := !sc.Scan()
+	for !eof {
+		skipScan := false
+		line := sc.Text()
+
+		switch {
+		case strings.HasPrefix(line, "River Reach="):
+			if reach != nil {
+				if err := emit(*reach); err != nil {
+					return errors.Wrap(err, 0)
+				}
+			}
+			riverReach := strings.Split(rightofEquals(line), ",")
+			reach = &reachGeometry{Name: fmt.Sprintf("%s, %s", strings.TrimSpace(riverReach[0]), strings.TrimSpace(riverReach[1]))}
+			reach.Centerline, skipScan, err = getReachXY(sc)
+			if err != nil {
+				return errors.Wrap(err, 0)
+			}
+
+		case strings.HasPrefix(line, "Type RM Length L Ch R =") && reach != nil:
+			data := strings.Split(rightofEquals(line), ",")
+			nodeType, err := strconv.Atoi(strings.TrimSpace(data[0]))
+			if err != nil {
+				return errors.Wrap(err, 0)
+			}
+
+			if nodeType == 1 {
+				xs, _, ss, err := getXSData(sc, 0, data)
+				skipScan = ss
+				if err != nil {
+					return errors.Wrap(err, 0)
+				}
+				reach.XS = append(reach.XS, xsOutline(xs))
+			} else if structureType, ok := structureTypes[nodeType]; ok {
+				location, ss, err := getStructureLocation(sc, structureType, data)
+				skipScan = ss
+				if err != nil {
+					return errors.Wrap(err, 0)
+				}
+				reach.Structures = append(reach.Structures, location)
+			}
+		}
+
+		// if a new RAS element is encountered during the functions call, scanning again will skip that element, therefore skip scan
+		if !skipScan {
+			eof = !sc.Scan()
+		}
+	}
+
+	if reach != nil {
+		if err := emit(*reach); err != nil {
+			return errors.Wrap(err, 0)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCrossingDistance(t *testing.T) {
+	centerline := [][2]float64{{0, 0}, {100, 0}, {100, 100}}
+
+	tests := []struct {
+		name     string
+		other    [][2]float64
+		distance float64
+		ok       bool
+	}{
+		{"first segment", [][2]float64{{50, -10}, {50, 10}}, 50, true},
+		{"second segment", [][2]float64{{90, 50}, {110, 50}}, 150, true},
+		{"at a vertex", [][2]float64{{90, 10}, {110, -10}}, 100, true},
+		{"multi segment line", [][2]float64{{20, 20}, {20, 10}, {30, -10}}, 25, true},
+		{"parallel", [][2]float64{{0, 5}, {90, 5}}, 0, false},
+		{"no crossing", [][2]float64{{50, 10}, {50, 20}}, 0, false},
+		{"single point", [][2]float64{{50, 0}}, 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			distance, ok := crossingDistance(centerline, tc.other)
+			if distance != tc.distance || ok != tc.ok {
+				t.Errorf("got (%v, %v), want (%v, %v)", distance, ok, tc.distance, tc.ok)
+			}
+		})
+	}
+}
+
+func TestLocateStructure(t *testing.T) {
+	centerline := [][2]float64{{0, 0}, {200, 0}}
+	crossings := []xsCrossing{
+		{Station: 300, Distance: 50, CutLineWidth: 40},
+		{Station: 100, Distance: 150, CutLineWidth: 60},
+	}
+
+	tests := []struct {
+		name      string
+		location  structureLocation
+		xyPairs   [][2]float64
+		locatedBy string
+	}{
+		{
+			"own cut line", structureLocation{Type: "Bridge", Station: 200, CutLine: [][2]float64{{5, 5}, {5, -5}}},
+			[][2]float64{{5, 5}, {5, -5}}, "Cut Line",
+		},
+		{
+			"interpolated across the centerline", structureLocation{Type: "Inline Structure", Station: 200},
+			[][2]float64{{100, 25}, {100, -25}}, "River Station",
+		},
+		{
+			"at a cross-section", structureLocation{Type: "Culvert", Station: 300},
+			[][2]float64{{50, 25}, {50, -25}}, "River Station",
+		},
+		{
+			"lateral structure along the centerline", structureLocation{Type: "Lateral Structure", Station: 250, Distance: 10, WeirLength: 30},
+			[][2]float64{{60, 0}, {90, 0}}, "River Station",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			xyPairs, locatedBy, err := locateStructure(tc.location, centerline, crossings)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(xyPairs, tc.xyPairs) || locatedBy != tc.locatedBy {
+				t.Errorf("got (%v, %q), want (%v, %q)", xyPairs, locatedBy, tc.xyPairs, tc.locatedBy)
+			}
+		})
+	}
+
+	errorTests := []struct {
+		name       string
+		location   structureLocation
+		centerline [][2]float64
+	}{
+		{"outside the cross-sections", structureLocation{Type: "Bridge", RS: "400", Station: 400}, centerline},
+		{"no centerline", structureLocation{Type: "Bridge", RS: "200", Station: 200}, [][2]float64{{0, 0}}},
+	}
+	for _, tc := range errorTests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, _, err := locateStructure(tc.location, tc.centerline, crossings); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"bufio"
+	"fmt"
 	"strconv"
 	"strings"
 
@@ -20,14 +21,35 @@ var conduitShapes map[int]string = map[int]string{
 	8: "High Arch",
 	9: "Conspan Arch"}
 
+// Map of HEC RAS Gate Type index to Gate Types
+var gateTypes map[int]string = map[int]string{
+	0: "Sluice",
+	1: "Radial",
+	2: "Overflow (open air)",
+	3: "Overflow (closed top)",
+	4: "User Defined Curves"}
+
+// Map of HEC RAS Gate Weir Shape index to Weir Shapes
+var gateWeirShapes map[int]string = map[int]string{
+	0:  "Broad Crested",
+	-1: "Ogee"}
+
+// Map of HEC RAS Culvert Solution Criteria index to Solution Criteria
+var culvertSolutionCriteria map[int]string = map[int]string{
+	0: "Highest U.S. EG",
+	1: "Inlet Control",
+	2: "Outlet Control"}
+
 // Store HEC-RAS 1D Hydraulic Structures
 type hydraulicStructures struct {
-	River       string      `json:"River Name"`
-	Reach       string      `json:"Reach Name"`
-	NumXS       int         `json:"Num CrossSections"`
-	CulvertData culvertData `json:"Culvert Data"`
-	BridgeData  bridgeData  `json:"Bridge Data"`
-	WeirData    weirData    `json:"Inline Weir Data"`
+	River         string          `json:"River Name"`
+	Reach         string          `json:"Reach Name"`
+	NumXS         int             `json:"Num CrossSections"`
+	CrossSections []crossSections `json:"Cross Sections"`
+	CulvertData   culvertData     `json:"Culvert Data"`
+	BridgeData    bridgeData      `json:"Bridge Data"`
+	WeirData      weirData        `json:"Inline Weir Data"`
+	LateralData   lateralData     `json:"Lateral Structure Data"`
 }
 
 type culvertData struct {
@@ -58,13 +80,24 @@ type maxMinPairs struct {
 
 // Store Culvert Groups in HEC-RAS 1D Culverts, 1D Inline Structures, and Connections
 type conduits struct {
-	Name       string
-	NumBarrels int `json:"Num Barrels"`
-	Shape      string
-	Rise       float64
-	Span       float64
-	Length     float64
-	ManningsN  float64 `json:"Mannings N"`
+	Name             string
+	NumBarrels       int `json:"Num Barrels"`
+	Shape            string
+	Rise             float64
+	Span             float64
+	Length           float64
+	ManningsN        float64   `json:"Mannings N"`
+	EntranceLoss     float64   `json:"Entrance Loss Coefficient"`
+	ExitLoss         float64   `json:"Exit Loss Coefficient"`
+	ChartNum         int       `json:"FHWA Chart"`
+	ScaleNum         int       `json:"FHWA Scale"`
+	UpInvert         float64   `json:"Upstream Invert Elevation"`
+	DownInvert       float64   `json:"Downstream Invert Elevation"`
+	UpStations       []float64 `json:"Upstream Barrel Stations"`
+	DownStations     []float64 `json:"Downstream Barrel Stations"`
+	SolutionCriteria string    `json:"Solution Criteria"`
+	BottomN          float64   `json:"Bottom Mannings N"`
+	DepthBlocked     float64   `json:"Depth Blocked"`
 }
 
 type bridgeData struct {
@@ -82,7 +115,16 @@ type bridges struct {
 	UpLowChord    maxMinPairs `json:"Upstream Low Chord"`
 	DownHighChord maxMinPairs `json:"Downstream High Chord"`
 	DownLowChord  maxMinPairs `json:"Downstream Low Chord"`
+	DeckDistance  float64     `json:"Deck Distance"`
+	WeirCoef      float64     `json:"Deck Weir Coefficient"`
+	Skew          float64     `json:"Deck Skew"`
+	MaxSubmerge   float64     `json:"Max Submergence"`
+	UpDeck        deckTable   `json:"Upstream Deck"`
+	DownDeck      deckTable   `json:"Downstream Deck"`
 	NumPiers      int         `json:"Num Piers"`
+	Piers         []piers
+	Abutments     []abutments
+	Approach      bridgeApproach `json:"Modeling Approach"`
 }
 
 type weirData struct {
@@ -101,14 +143,59 @@ type weirs struct {
 	Gates       []gates
 	NumConduits int        `json:"Num Culvert Conduits"`
 	Conduits    []conduits `json:"Culvert Conduits"`
+	Breach      *breaches  `json:"Breach,omitempty"`
+}
+
+// Map of HEC RAS Lateral Weir Position index to Lateral Structure Positions
+var lateralPositions map[int]string = map[int]string{
+	0: "Left Overbank",
+	1: "Right Overbank",
+	2: "Left Overbank Out of Bank",
+	3: "Right Overbank Out of Bank"}
+
+type lateralData struct {
+	NumLaterals int        `json:"Num Lateral Structures"`
+	Laterals    []laterals `json:"Lateral Structures"`
+}
+
+// Store HEC-RAS 1D Lateral Structures
+type laterals struct {
+	Name           string
+	Station        float64
+	Description    string
+	Position       string
+	Distance       float64     `json:"Distance to Upstream XS"`
+	WeirWidth      float64     `json:"Weir Width"`
+	WeirElev       maxMinPairs `json:"Weir Elevations"`
+	NumGates       int         `json:"Num Gates"`
+	Gates          []gates
+	NumConduits    int        `json:"Num Culvert Conduits"`
+	Conduits       []conduits `json:"Culvert Conduits"`
+	ConnectedReach string     `json:"Connected Reach"`
+	ConnectedRS    string     `json:"Connected RS"`
+	ConnectedSA    string     `json:"Connected Storage Area"`
+	Connected2D    string     `json:"Connected 2D Area"`
+	Breach         *breaches  `json:"Breach,omitempty"`
 }
 
-// Store Gates Groups in HEC-RAS 1D Inline Structures and Connections
+// Store Gates Groups in HEC-RAS 1D Inline Structures, Lateral Structures and Connections
 type gates struct {
-	Name        string
-	Width       float64
-	Height      float64
-	NumOpenings int `json:"Num Openings"`
+	Name            string
+	Type            string
+	Width           float64
+	Height          float64
+	SillElev        float64   `json:"Sill Elevation"`
+	DischargeCoef   float64   `json:"Discharge Coefficient"`
+	TrunnionExp     float64   `json:"Trunnion Exponent"`
+	OpeningExp      float64   `json:"Opening Exponent"`
+	HeadExp         float64   `json:"Head Exponent"`
+	TrunnionHeight  float64   `json:"Trunnion Height"`
+	WeirCoef        float64   `json:"Weir Coefficient"`
+	WeirShape       string    `json:"Weir Shape"`
+	SpillwayHeight  float64   `json:"Spillway Approach Height"`
+	DesignHead      float64   `json:"Design Energy Head"`
+	NumOpenings     int       `json:"Num Openings"`
+	OpeningStations []float64 `json:"Opening Centerline Stations"`
 }
 
 // Return elevation data from HEC-RAS Station-Elevation (SE) block of text
@@ -222,15 +309,30 @@ func stringtoFloat(s string) (float64, error) {
 }
 
 // Extract data from Culvert Groups in HEC-RAS 1D Culverts,
-// 1D Inline Structures, and Connections
+// 1D Inline Structures, and Connections.
+// Single barrel culverts store their barrel stations on the culvert line, multiple barrel culverts store them on the following lines.
 func getConduits(line string, single bool) (conduits, error) {
 	lineData := strings.Split(rightofEquals(line), ",")
 	conduit := conduits{}
 
+	// position of the values that differ between single and multiple barrel culverts
+	var solutionIdx, bottomNIdx, depthBlockedIdx int
 	if single {
 		conduit.NumBarrels = 1
 		conduit.Name = strings.TrimSpace(lineData[13])
 
+		upStation, err := stringtoFloat(lineValue(lineData, 10))
+		if err != nil {
+			return conduit, errors.Wrap(err, 0)
+		}
+		downStation, err := stringtoFloat(lineValue(lineData, 12))
+		if err != nil {
+			return conduit, errors.Wrap(err, 0)
+		}
+		conduit.UpStations = []float64{upStation}
+		conduit.DownStations = []float64{downStation}
+		solutionIdx, bottomNIdx, depthBlockedIdx = 14, 16, 17
+
 	} else {
 		numbarrels, err := strconv.Atoi(strings.TrimSpace(lineData[11]))
 		if err != nil {
@@ -239,6 +341,7 @@ func getConduits(line string, single bool) (conduits, error) {
 		}
 		conduit.NumBarrels = numbarrels
 		conduit.Name = strings.TrimSpace(lineData[12])
+		solutionIdx, bottomNIdx, depthBlockedIdx = 13, 15, 16
 	}
 
 	shapeID, err := strconv.Atoi(strings.TrimSpace(lineData[0]))
@@ -248,44 +351,91 @@ func getConduits(line string, single bool) (conduits, error) {
 	}
 	conduit.Shape = conduitShapes[shapeID]
 
-	rise, err := stringtoFloat(lineData[1])
-	if err != nil {
-		return conduit, errors.Wrap(err, 0)
-
+	values := map[int]*float64{
+		1:               &conduit.Rise,
+		2:               &conduit.Span,
+		3:               &conduit.Length,
+		4:               &conduit.ManningsN,
+		5:               &conduit.EntranceLoss,
+		6:               &conduit.ExitLoss,
+		9:               &conduit.UpInvert,
+		bottomNIdx:      &conduit.BottomN,
+		depthBlockedIdx: &conduit.DepthBlocked,
+	}
+	if single {
+		values[11] = &conduit.DownInvert
+	} else {
+		values[10] = &conduit.DownInvert
+	}
+	for idx, v := range values {
+		val, err := stringtoFloat(lineValue(lineData, idx))
+		if err != nil {
+			return conduit, errors.Wrap(err, 0)
+		}
+		*v = val
 	}
-	conduit.Rise = rise
 
-	span, err := stringtoFloat(lineData[2])
-	if err != nil {
-		return conduit, errors.Wrap(err, 0)
+	for idx, v := range map[int]*int{7: &conduit.ChartNum, 8: &conduit.ScaleNum} {
+		if lineValue(lineData, idx) == "" {
+			continue
+		}
+		val, err := strconv.Atoi(lineValue(lineData, idx))
+		if err != nil {
+			return conduit, errors.Wrap(err, 0)
+		}
+		*v = val
+	}
 
+	if lineValue(lineData, solutionIdx) != "" {
+		solutionID, err := strconv.Atoi(lineValue(lineData, solutionIdx))
+		if err != nil {
+			return conduit, errors.Wrap(err, 0)
+		}
+		if criteria, ok := culvertSolutionCriteria[solutionID]; ok {
+			conduit.SolutionCriteria = criteria
+		} else {
+			conduit.SolutionCriteria = strconv.Itoa(solutionID)
+		}
 	}
-	conduit.Span = span
 
-	length, err := stringtoFloat(lineData[3])
-	if err != nil {
-		return conduit, errors.Wrap(err, 0)
+	return conduit, nil
+}
 
+// Return the trimmed value at position idx of a comma separated line, or an empty string if the line is shorter
+func lineValue(lineData []string, idx int) string {
+	if idx >= len(lineData) {
+		return ""
 	}
-	conduit.Length = length
+	return strings.TrimSpace(lineData[idx])
+}
 
-	mannings, err := stringtoFloat(lineData[4])
+// Extract barrel stations of a multiple barrel culvert from the block of text following its culvert line.
+// Upstream stations of all barrels are followed by downstream stations.
+func getBarrelStations(hsSc *bufio.Scanner, i int, conduit *conduits) (int, error) {
+	nValues := conduit.NumBarrels * 2
+	if nValues == 0 {
+		return i, nil
+	}
+	values, i, err := datafromTextBlock(hsSc, i, numberofLines(nValues, 80, 8), 0, 80, 8, 1)
 	if err != nil {
-		return conduit, errors.Wrap(err, 0)
-
+		return i, errors.Wrap(err, 0)
 	}
-	conduit.ManningsN = mannings
-
-	return conduit, nil
+	if len(values) < nValues {
+		return i, errors.Errorf("Failed to parse barrel stations of culvert '%s'", conduit.Name)
+	}
+	conduit.UpStations = values[:conduit.NumBarrels]
+	conduit.DownStations = values[conduit.NumBarrels:nValues]
+	return i, nil
 }
 
-// Extract data from HEC-RAS 1D Culverts
-func getCulvertData(hsSc *bufio.Scanner, i int, lineData []string) (culverts, int, error) {
+// Extract data from HEC-RAS 1D Culverts.
+// Returns when a new geometry element is encountered, in which case the next scan must be skipped.
+func getCulvertData(hsSc *bufio.Scanner, i int, lineData []string) (culverts, int, bool, error) {
 	culvert := culverts{}
 
 	station, err := parseFloat(strings.TrimSpace(lineData[1]), 64)
 	if err != nil {
-		return culvert, i, errors.Wrap(err, 0)
+		return culvert, i, false, errors.Wrap(err, 0)
 	}
 	culvert.Station = station
 
@@ -297,7 +447,7 @@ func getCulvertData(hsSc *bufio.Scanner, i int, lineData []string) (culverts, in
 			var description string
 			description, i, err = getDescription(hsSc, i, "END DESCRIPTION:")
 			if err != nil {
-				return culvert, i, errors.Wrap(err, 0)
+				return culvert, i, false, errors.Wrap(err, 0)
 			}
 			culvert.Description += description
 
@@ -310,14 +460,14 @@ func getCulvertData(hsSc *bufio.Scanner, i int, lineData []string) (culverts, in
 			nextLineData := strings.Split(hsSc.Text(), ",")
 			deckWidth, err := parseFloat(strings.TrimSpace(nextLineData[0]), 64)
 			if err != nil {
-				return culvert, i, errors.Wrap(err, 0)
+				return culvert, i, false, errors.Wrap(err, 0)
 			}
 			culvert.DeckWidth = deckWidth
 
 			var upHighLowPair [2]maxMinPairs
 			upHighLowPair, i, err = getHighLowChord(hsSc, i, nextLineData[4], 80, 8)
 			if err != nil {
-				return culvert, i, errors.Wrap(err, 0)
+				return culvert, i, false, errors.Wrap(err, 0)
 			}
 			culvert.UpHighChord = upHighLowPair[0]
 			culvert.UpLowChord = upHighLowPair[1]
@@ -325,7 +475,7 @@ func getCulvertData(hsSc *bufio.Scanner, i int, lineData []string) (culverts, in
 			var downHighLowPair [2]maxMinPairs
 			downHighLowPair, i, err = getHighLowChord(hsSc, i, nextLineData[5], 80, 8)
 			if err != nil {
-				return culvert, i, errors.Wrap(err, 0)
+				return culvert, i, false, errors.Wrap(err, 0)
 			}
 			culvert.DownHighChord = downHighLowPair[0]
 			culvert.DownLowChord = downHighLowPair[1]
@@ -333,7 +483,7 @@ func getCulvertData(hsSc *bufio.Scanner, i int, lineData []string) (culverts, in
 		case strings.HasPrefix(line, "Culvert="):
 			conduit, err := getConduits(line, true)
 			if err != nil {
-				return culvert, i, errors.Wrap(err, 0)
+				return culvert, i, false, errors.Wrap(err, 0)
 			}
 			culvert.Conduits = append(culvert.Conduits, conduit)
 			culvert.NumConduits++
@@ -341,28 +491,31 @@ func getCulvertData(hsSc *bufio.Scanner, i int, lineData []string) (culverts, in
 		case strings.HasPrefix(line, "Multiple Barrel Culv="):
 			conduit, err := getConduits(line, false)
 			if err != nil {
-				return culvert, i, errors.Wrap(err, 0)
+				return culvert, i, false, errors.Wrap(err, 0)
+			}
+			i, err = getBarrelStations(hsSc, i, &conduit)
+			if err != nil {
+				return culvert, i, false, errors.Wrap(err, 0)
 			}
 			culvert.Conduits = append(culvert.Conduits, conduit)
 			culvert.NumConduits++
 
-		case strings.HasPrefix(line, "Type RM Length L Ch R ="):
-			return culvert, i, nil
-
-		case strings.HasPrefix(line, "River Reach="):
-			return culvert, i, nil
+		case stringInSlice(leftofEquals(line), geomElementsPrefix[:]):
+			// a new HEC RAS element has been encountered, skip next scan and return
+			return culvert, i, true, nil
 		}
 	}
-	return culvert, i, nil
+	return culvert, i, false, nil
 }
 
-// Extract data from 1D Bridges
-func getBridgeData(hsSc *bufio.Scanner, i int, lineData []string) (bridges, int, error) {
+// Extract data from 1D Bridges.
+// Returns when a new geometry element is encountered, in which case the next scan must be skipped.
+func getBridgeData(hsSc *bufio.Scanner, i int, lineData []string) (bridges, int, bool, error) {
 	bridge := bridges{}
 
 	station, err := parseFloat(strings.TrimSpace(lineData[1]), 64)
 	if err != nil {
-		return bridge, i, errors.Wrap(err, 0)
+		return bridge, i, false, errors.Wrap(err, 0)
 	}
 	bridge.Station = station
 
@@ -374,7 +527,7 @@ func getBridgeData(hsSc *bufio.Scanner, i int, lineData []string) (bridges, int,
 			var description string
 			description, i, err = getDescription(hsSc, i, "END DESCRIPTION:")
 			if err != nil {
-				return bridge, i, errors.Wrap(err, 0)
+				return bridge, i, false, errors.Wrap(err, 0)
 			}
 			bridge.Description += description
 
@@ -387,40 +540,70 @@ func getBridgeData(hsSc *bufio.Scanner, i int, lineData []string) (bridges, int,
 			nextLineData := strings.Split(hsSc.Text(), ",")
 			deckWidth, err := parseFloat(strings.TrimSpace(nextLineData[0]), 64)
 			if err != nil {
-				return bridge, i, errors.Wrap(err, 0)
+				return bridge, i, false, errors.Wrap(err, 0)
 			}
 			bridge.DeckWidth = deckWidth
 
+			// values are distance, width, weir coefficient, skew, num upstream, num downstream, min low chord, max high chord, max submergence
+			deckValues := map[int]*float64{0: &bridge.DeckDistance, 2: &bridge.WeirCoef, 3: &bridge.Skew, 8: &bridge.MaxSubmerge}
+			for idx, v := range deckValues {
+				val, err := stringtoFloat(lineValue(nextLineData, idx))
+				if err != nil {
+					return bridge, i, false, errors.Wrap(err, 0)
+				}
+				*v = val
+			}
+
 			var upHighLowPair [2]maxMinPairs
-			upHighLowPair, i, err = getHighLowChord(hsSc, i, nextLineData[4], 80, 8)
+			bridge.UpDeck, upHighLowPair, i, err = getDeckTable(hsSc, i, nextLineData[4])
 			if err != nil {
-				return bridge, i, errors.Wrap(err, 0)
+				return bridge, i, false, errors.Wrap(err, 0)
 			}
 			bridge.UpHighChord = upHighLowPair[0]
 			bridge.UpLowChord = upHighLowPair[1]
 
 			var downHighLowPair [2]maxMinPairs
-			downHighLowPair, i, err = getHighLowChord(hsSc, i, nextLineData[5], 80, 8)
+			bridge.DownDeck, downHighLowPair, i, err = getDeckTable(hsSc, i, nextLineData[5])
 			if err != nil {
-				return bridge, i, errors.Wrap(err, 0)
+				return bridge, i, false, errors.Wrap(err, 0)
 			}
 			bridge.DownHighChord = downHighLowPair[0]
 			bridge.DownLowChord = downHighLowPair[1]
 
 		case strings.HasPrefix(line, "Pier Skew"):
+			var pier piers
+			pier, i, err = getPier(hsSc, i, line)
+			if err != nil {
+				return bridge, i, false, errors.Wrap(err, 0)
+			}
+			bridge.Piers = append(bridge.Piers, pier)
 			bridge.NumPiers++
 
-		case strings.HasPrefix(line, "Type RM Length L Ch R ="):
-			return bridge, i, nil
+		case strings.HasPrefix(line, "Abutment Skew"):
+			var abutment abutments
+			abutment, i, err = getAbutment(hsSc, i, line)
+			if err != nil {
+				return bridge, i, false, errors.Wrap(err, 0)
+			}
+			bridge.Abutments = append(bridge.Abutments, abutment)
+
+		case strings.HasPrefix(line, "BR Coef="):
+			bridge.Approach, err = getBridgeApproach(line)
+			if err != nil {
+				return bridge, i, false, errors.Wrap(err, 0)
+			}
 
-		case strings.HasPrefix(line, "River Reach="):
-			return bridge, i, nil
+		case stringInSlice(leftofEquals(line), geomElementsPrefix[:]):
+			// a new HEC RAS element has been encountered, skip next scan and return
+			return bridge, i, true, nil
 		}
 	}
-	return bridge, i, nil
+	return bridge, i, false, nil
 }
 
-// Extract data from Gates Groups in 1D Inline Structures and Connections
+// Extract data from Gates Groups in 1D Inline Structures and Connections.
+// Values are name, width, height, invert, gate coefficient, trunnion exponent, opening exponent, head exponent,
+// type, weir coefficient, is ogee, spillway approach height, design energy head, number of openings and trunnion height.
 func getGates(nextLine string) (gates, error) {
 	gate := gates{}
 
@@ -428,19 +611,53 @@ func getGates(nextLine string) (gates, error) {
 
 	gate.Name = strings.TrimSpace(nextLineData[0])
 
-	width, err := stringtoFloat(nextLineData[1])
-	if err != nil {
-		return gate, errors.Wrap(err, 0)
+	values := map[int]*float64{
+		1:  &gate.Width,
+		2:  &gate.Height,
+		3:  &gate.SillElev,
+		4:  &gate.DischargeCoef,
+		5:  &gate.TrunnionExp,
+		6:  &gate.OpeningExp,
+		7:  &gate.HeadExp,
+		9:  &gate.WeirCoef,
+		11: &gate.SpillwayHeight,
+		12: &gate.DesignHead,
+		14: &gate.TrunnionHeight,
+	}
+	for idx, v := range values {
+		val, err := stringtoFloat(lineValue(nextLineData, idx))
+		if err != nil {
+			return gate, errors.Wrap(err, 0)
+		}
+		*v = val
+	}
 
+	if gateType := lineValue(nextLineData, 8); gateType != "" {
+		typeID, err := strconv.Atoi(gateType)
+		if err != nil {
+			return gate, errors.Wrap(err, 0)
+		}
+		if t, ok := gateTypes[typeID]; ok {
+			gate.Type = t
+		} else {
+			gate.Type = strconv.Itoa(typeID)
+		}
 	}
-	gate.Width = width
 
-	height, err := stringtoFloat(nextLineData[2])
+	weirShape := lineValue(nextLineData, 10)
+	if weirShape == "" {
+		// broad crested unless specified
+		weirShape = "0"
+	}
+	shapeID, err := strconv.Atoi(weirShape)
 	if err != nil {
 		return gate, errors.Wrap(err, 0)
-
 	}
-	gate.Height = height
+	if shape, ok := gateWeirShapes[shapeID]; ok {
+		gate.WeirShape = shape
+	} else {
+		gate.WeirShape = strconv.Itoa(shapeID)
+	}
 
 	numopenings, err := strconv.Atoi(strings.TrimSpace(nextLineData[13]))
 	if err != nil {
@@ -452,6 +669,17 @@ func getGates(nextLine string) (gates, error) {
 	return gate, nil
 }
 
+// Extract the centerline stations of the openings of a gate group from the block of text following the gate line.
+// Returns the number of lines read.
+func getGateOpenings(sc *bufio.Scanner, gate *gates) (int, error) {
+	stations, nLines, err := floatSeriesFromTextBlock(sc, 0, gate.NumOpenings)
+	if err != nil {
+		return nLines, errors.Wrap(err, 0)
+	}
+	gate.OpeningStations = stations
+	return nLines, nil
+}
+
 // Extract data from Inline Structures
 func getWeirData(rm *RasModel, fn string, i int) (weirs, error) {
 	weir := weirs{}
@@ -515,12 +743,18 @@ func getWeirData(rm *RasModel, fn string, i int) (weirs, error) {
 				weir.WeirWidth = weirWidth
 
 			case strings.HasPrefix(line, "IW Gate Name"):
+				wi++
 				wSc.Scan()
 				gate, err := getGates(wSc.Text())
 				if err != nil {
 					return weir, errors.Wrap(err, 0)
 
 				}
+				nLines, err := getGateOpenings(wSc, &gate)
+				wi += nLines
+				if err != nil {
+					return weir, errors.Wrap(err, 0)
+				}
 				weir.Gates = append(weir.Gates, gate)
 				weir.NumGates++
 
@@ -544,12 +778,116 @@ func getWeirData(rm *RasModel, fn string, i int) (weirs, error) {
 	return weir, nil
 }
 
-// Extract all data from 1D Bridges, Culverts, and Inline Structures
+// Extract data from 1D Lateral Structures.
+// Returns when a new geometry element is encountered, in which case the next scan must be skipped.
+func getLateralData(hsSc *bufio.Scanner, i int, lineData []string) (laterals, int, bool, error) {
+	lateral := laterals{}
+
+	station, err := parseFloat(strings.TrimSpace(lineData[1]), 64)
+	if err != nil {
+		return lateral, i, false, errors.Wrap(err, 0)
+	}
+	lateral.Station = station
+
+	for hsSc.Scan() {
+		i++
+		line := hsSc.Text()
+		switch {
+		case strings.HasPrefix(line, "BEGIN DESCRIPTION"):
+			var description string
+			description, i, err = getDescription(hsSc, i, "END DESCRIPTION:")
+			if err != nil {
+				return lateral, i, false, errors.Wrap(err, 0)
+			}
+			lateral.Description += description
+
+		case strings.HasPrefix(line, "Node Name="):
+			lateral.Name = rightofEquals(line)
+
+		case strings.HasPrefix(line, "Lateral Weir Pos="):
+			positionID, err := strconv.Atoi(strings.TrimSpace(rightofEquals(line)))
+			if err != nil {
+				return lateral, i, false, errors.Wrap(err, 0)
+			}
+			lateral.Position = lateralPositions[positionID]
+
+		case strings.HasPrefix(line, "Lateral Weir End="):
+			endData := strings.Split(rightofEquals(line), ",")
+			if len(endData) >= 4 {
+				if river, reach := strings.TrimSpace(endData[0]), strings.TrimSpace(endData[1]); river != "" {
+					lateral.ConnectedReach = fmt.Sprintf("%s, %s", river, reach)
+					lateral.ConnectedRS = strings.TrimSpace(endData[2])
+				}
+				// the connected area is classified as a storage area or 2D area once all areas are known
+				lateral.ConnectedSA = strings.TrimSpace(endData[3])
+			}
+
+		case strings.HasPrefix(line, "Lateral Weir Distance="):
+			lateral.Distance, err = stringtoFloat(rightofEquals(line))
+			if err != nil {
+				return lateral, i, false, errors.Wrap(err, 0)
+			}
+
+		case strings.HasPrefix(line, "Lateral Weir WD="):
+			lateral.WeirWidth, err = stringtoFloat(rightofEquals(line))
+			if err != nil {
+				return lateral, i, false, errors.Wrap(err, 0)
+			}
+
+		case strings.HasPrefix(line, "#Lateral Weir SE="):
+			nElev, err := strconv.Atoi(strings.TrimSpace(rightofEquals(line)))
+			if err != nil {
+				return lateral, i, false, errors.Wrap(err, 0)
+			}
+			nLines := numberofLines(nElev*2, 80, 8)
+
+			lateral.WeirElev, i, err = getMaxMinElev(hsSc, i, nLines, 0, 80, 8, 2)
+			if err != nil {
+				return lateral, i, false, errors.Wrap(err, 0)
+			}
+
+		case strings.HasPrefix(line, "LW Gate Name"):
+			i++
+			hsSc.Scan()
+			gate, err := getGates(hsSc.Text())
+			if err != nil {
+				return lateral, i, false, errors.Wrap(err, 0)
+			}
+			nLines, err := getGateOpenings(hsSc, &gate)
+			i += nLines
+			if err != nil {
+				return lateral, i, false, errors.Wrap(err, 0)
+			}
+			lateral.Gates = append(lateral.Gates, gate)
+			lateral.NumGates++
+
+		case strings.HasPrefix(line, "LW Culv="):
+			conduit, err := getConduits(line, false)
+			if err != nil {
+				return lateral, i, false, errors.Wrap(err, 0)
+			}
+			i, err = getBarrelStations(hsSc, i, &conduit)
+			if err != nil {
+				return lateral, i, false, errors.Wrap(err, 0)
+			}
+			lateral.Conduits = append(lateral.Conduits, conduit)
+			lateral.NumConduits++
+
+		case stringInSlice(leftofEquals(line), geomElementsPrefix[:]):
+			// a new HEC RAS element has been encountered, skip next scan and return
+			return lateral, i, true, nil
+		}
+	}
+	return lateral, i, false, nil
+}
+
+// Extract all data from 1D Cross Sections, Bridges, Culverts, Inline and Lateral Structures
 func getHydraulicStructureData(rm *RasModel, fn string, idx int) (hydraulicStructures, error) {
 	structures := hydraulicStructures{}
 	bData := bridgeData{}
 	cData := culvertData{}
 	wData := weirData{}
+	lData := lateralData{}
 
 	f, err := rm.FileStore.GetObject(fn)
 	if err != nil {
@@ -560,15 +898,19 @@ func getHydraulicStructureData(rm *RasModel, fn string, idx int) (hydraulicStruc
 
 	hsSc := bufio.NewScanner(f)
 
-	i := 0
-	for hsSc.Scan() {
-		i++
+	i := 1
+	eof := !hsSc.Scan()
+	for !eof {
+		skipScan := false
 		if i == idx {
 			riverReach := strings.Split(rightofEquals(hsSc.Text()), ",")
 			structures.River = strings.TrimSpace(riverReach[0])
 			structures.Reach = strings.TrimSpace(riverReach[1])
 		} else if i > idx {
 			line := hsSc.Text()
+			if strings.HasPrefix(line, "River Reach=") {
+				break
+			}
 			if strings.HasPrefix(line, "Type RM Length L Ch R =") {
 				data := strings.Split(rightofEquals(line), ",")
 				structureType, err := strconv.Atoi(strings.TrimSpace(data[0]))
@@ -578,11 +920,18 @@ func getHydraulicStructureData(rm *RasModel, fn string, idx int) (hydraulicStruc
 				}
 				switch structureType {
 				case 1:
+					var xs crossSections
+					xs, i, skipScan, err = getXSData(hsSc, i, data)
+					if err != nil {
+						return structures, errors.Wrap(err, 0)
+
+					}
+					structures.CrossSections = append(structures.CrossSections, xs)
 					structures.NumXS++
 
 				case 2:
 					var culvert culverts
-					culvert, i, err = getCulvertData(hsSc, i, data)
+					culvert, i, skipScan, err = getCulvertData(hsSc, i, data)
 					if err != nil {
 						return structures, errors.Wrap(err, 0)
 
@@ -592,7 +941,7 @@ func getHydraulicStructureData(rm *RasModel, fn string, idx int) (hydraulicStruc
 
 				case 3:
 					var bridge bridges
-					bridge, i, err = getBridgeData(hsSc, i, data)
+					bridge, i, skipScan, err = getBridgeData(hsSc, i, data)
 					if err != nil {
 						return structures, errors.Wrap(err, 0)
 
@@ -608,19 +957,30 @@ func getHydraulicStructureData(rm *RasModel, fn string, idx int) (hydraulicStruc
 					}
 					wData.Weirs = append(wData.Weirs, weir)
 					wData.NumWeirs++
+
+				case 6:
+					var lateral laterals
+					lateral, i, skipScan, err = getLateralData(hsSc, i, data)
+					if err != nil {
+						return structures, errors.Wrap(err, 0)
+
+					}
+					lData.Laterals = append(lData.Laterals, lateral)
+					lData.NumLaterals++
 				}
 			}
-			if strings.HasPrefix(line, "River Reach=") {
-				structures.CulvertData = cData
-				structures.BridgeData = bData
-				structures.WeirData = wData
-				return structures, nil
-			}
+		}
+
+		// if a new RAS element is encountered during the functions call, scanning again will skip that element, therefore skip scan
+		if !skipScan {
+			eof = !hsSc.Scan()
+			i++
 		}
 	}
 	structures.CulvertData = cData
 	structures.BridgeData = bData
 	structures.WeirData = wData
+	structures.LateralData = lData
 
 	return structures, nil
 }
@@ -0,0 +1,180 @@
+package tools
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/USACE/filestore"
+)
+
+func TestGetCulvertDataBarrelStations(t *testing.T) {
+	text := strings.Join([]string{
+		"Node Name=Main St",
+		"Multiple Barrel Culv=1,4,4,50,0.013,0.5,1,1,1,100,99.5,3,Culvert #1,0,0,0.02,0.5",
+		"     120     130     140     121     131     141",
+		"Culvert Note Without Values",
+		"Multiple Barrel Culv=2,6,6,50,0.024,0.5,1,2,1,100,99.5,1,Culvert #2,1,0,0,0",
+		"     150     151",
+		"Type RM Length L Ch R = 1 ,90     ,100,100,100",
+	}, "\n")
+	sc := bufio.NewScanner(strings.NewReader(text))
+	culvert, _, skipScan, err := getCulvertData(sc, 0, []string{"2", "95"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !skipScan {
+		t.Error("expected the next element to be left to the caller")
+	}
+	if culvert.Name != "Main St" || culvert.NumConduits != 2 {
+		t.Fatalf("unexpected culvert %+v", culvert)
+	}
+
+	tests := []struct {
+		up   []float64
+		down []float64
+	}{
+		{[]float64{120, 130, 140}, []float64{121, 131, 141}},
+		{[]float64{150}, []float64{151}},
+	}
+	for n, tc := range tests {
+		conduit := culvert.Conduits[n]
+		if !reflect.DeepEqual(conduit.UpStations, tc.up) || !reflect.DeepEqual(conduit.DownStations, tc.down) {
+			t.Errorf("conduit %d: got stations %v %v, want %v %v", n, conduit.UpStations, conduit.DownStations, tc.up, tc.down)
+		}
+	}
+}
+
+func TestGetConduits(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		single   bool
+		expected conduits
+	}{
+		{
+			"single barrel", "Culvert=2,6,5,50,0.013,0.5,1,8,1,100,120,99.5,121,Culvert #1,0,0,0.02,0.5", true,
+			conduits{
+				Name: "Culvert #1", NumBarrels: 1, Shape: "Box", Rise: 6, Span: 5, Length: 50, ManningsN: 0.013,
+				EntranceLoss: 0.5, ExitLoss: 1, ChartNum: 8, ScaleNum: 1, UpInvert: 100, DownInvert: 99.5,
+				UpStations: []float64{120}, DownStations: []float64{121}, SolutionCriteria: "Highest U.S. EG", BottomN: 0.02, DepthBlocked: 0.5,
+			},
+		},
+		{
+			"multiple barrels", "Multiple Barrel Culv=1,4,4,60,0.024,0.7,1,1,2,101,100.5,3,Culvert #2,2,0,0,0", false,
+			conduits{
+				Name: "Culvert #2", NumBarrels: 3, Shape: "Circular", Rise: 4, Span: 4, Length: 60, ManningsN: 0.024,
+				EntranceLoss: 0.7, ExitLoss: 1, ChartNum: 1, ScaleNum: 2, UpInvert: 101, DownInvert: 100.5, SolutionCriteria: "Outlet Control",
+			},
+		},
+		{
+			"unknown solution criteria and no chart", "Multiple Barrel Culv=9,4,4,60,0.024,0.7,1,,,101,100.5,2,Culvert #3,5", false,
+			conduits{
+				Name: "Culvert #3", NumBarrels: 2, Shape: "Conspan Arch", Rise: 4, Span: 4, Length: 60, ManningsN: 0.024,
+				EntranceLoss: 0.7, ExitLoss: 1, UpInvert: 101, DownInvert: 100.5, SolutionCriteria: "5",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			conduit, err := getConduits(tc.line, tc.single)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(conduit, tc.expected) {
+				t.Errorf("got %+v, want %+v", conduit, tc.expected)
+			}
+		})
+	}
+
+	if _, err := getConduits("Culvert=2,6,5,fifty,0.013,0.5,1,8,1,100,120,99.5,121,Culvert #1", true); err == nil {
+		t.Error("expected an error for a non numeric length")
+	}
+}
+
+func TestGetGates(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		expected gates
+	}{
+		{
+			"radial ogee gate", "Gate #1     ,10,8,100,0.6,0.16,1,0.62,1,3.9,-1,2,5,3,4",
+			gates{
+				Name: "Gate #1", Type: "Radial", Width: 10, Height: 8, SillElev: 100, DischargeCoef: 0.6, TrunnionExp: 0.16,
+				OpeningExp: 1, HeadExp: 0.62, WeirCoef: 3.9, WeirShape: "Ogee", SpillwayHeight: 2, DesignHead: 5, NumOpenings: 3, TrunnionHeight: 4,
+			},
+		},
+		{
+			"sluice gate with a broad crested weir", "Gate #2,12,6,98,0.8,,,,0,2.6,0,,,1",
+			gates{
+				Name: "Gate #2", Type: "Sluice", Width: 12, Height: 6, SillElev: 98, DischargeCoef: 0.8, WeirCoef: 2.6, WeirShape: "Broad Crested", NumOpenings: 1,
+			},
+		},
+		{
+			"unknown type with a broad crested weir by default", "Gate #3,12,6,98,0.8,,,,7,2.6,,,,2",
+			gates{
+				Name: "Gate #3", Type: "7", Width: 12, Height: 6, SillElev: 98, DischargeCoef: 0.8, WeirCoef: 2.6, WeirShape: "Broad Crested", NumOpenings: 2,
+			},
+		},
+		{
+			"unknown weir shape", "Gate #4,12,6,98,0.8,,,,0,2.6,2,,,1",
+			gates{
+				Name: "Gate #4", Type: "Sluice", Width: 12, Height: 6, SillElev: 98, DischargeCoef: 0.8, WeirCoef: 2.6, WeirShape: "2", NumOpenings: 1,
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gate, err := getGates(tc.line)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gate, tc.expected) {
+				t.Errorf("got %+v, want %+v", gate, tc.expected)
+			}
+		})
+	}
+
+	if _, err := getGates("Gate #5,12,6,98,0.8,,,,0,2.6,0,,,many"); err == nil {
+		t.Error("expected an error for a non numeric number of openings")
+	}
+	if _, err := getGates("Gate #5,12,6,98,0.8,,,,0,2.6,ogee,,,1"); err == nil {
+		t.Error("expected an error for a non numeric weir shape")
+	}
+}
+
+func TestGetHydraulicStructureDataLateral(t *testing.T) {
+	rm := RasModel{FileStore: &filestore.BlockFS{}}
+	structures, err := getHydraulicStructureData(&rm, "testdata/lateral.g01", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if structures.River != "Bald Eagle Cr." || structures.Reach != "Lock Haven" || structures.NumXS != 2 {
+		t.Errorf("got river %q, reach %q and %d cross sections", structures.River, structures.Reach, structures.NumXS)
+	}
+	if structures.LateralData.NumLaterals != 1 {
+		t.Fatalf("got %d lateral structures, want 1", structures.LateralData.NumLaterals)
+	}
+
+	lateral := structures.LateralData.Laterals[0]
+	if lateral.Name != "Levee Outlets" || lateral.Station != 1150 || lateral.ConnectedSA != "Pond" || lateral.NumConduits != 2 {
+		t.Fatalf("unexpected lateral structure %+v", lateral)
+	}
+
+	tests := []struct {
+		name string
+		up   []float64
+		down []float64
+	}{
+		{"Outlets", []float64{120, 130, 140}, []float64{121, 131, 141}},
+		{"Outlet #2", []float64{300}, []float64{301}},
+	}
+	for n, tc := range tests {
+		conduit := lateral.Conduits[n]
+		if conduit.Name != tc.name || !reflect.DeepEqual(conduit.UpStations, tc.up) || !reflect.DeepEqual(conduit.DownStations, tc.down) {
+			t.Errorf("conduit %d: got %q with stations %v %v, want %q with %v %v", n, conduit.Name, conduit.UpStations, conduit.DownStations, tc.name, tc.up, tc.down)
+		}
+	}
+}
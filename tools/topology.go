@@ -0,0 +1,354 @@
+package tools
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-errors/errors" // warning: replaces standard errors
+)
+
+// Topology is a directed graph of the reaches, storage areas and 2D areas of a geometry file.
+// Edges are junctions, lateral structures, SA/2D area connections and pump stations.
+type Topology struct {
+	GeomFile   string              `json:"Geometry File"`
+	Nodes      map[string]TopoNode `json:"Nodes"`
+	Edges      []TopoEdge          `json:"Edges"`
+	Headwaters []string            `json:"Headwater Reaches"`
+	Outlets    []string            `json:"Outlet Reaches"`
+	Components []TopoComponent     `json:"Components"`
+}
+
+// Reach, Storage Area or 2D Area
+type TopoNode struct {
+	Type          string
+	CrossSections []crossSections `json:"-"` // only used to trace from a river station
+}
+
+// Flow path between two nodes of the topology
+type TopoEdge struct {
+	From string
+	To   string
+	Type string
+	Name string
+	RS   string `json:",omitempty"` // river station of the structure on the From reach, only exists for lateral structures and pump stations
+	ToRS string `json:"To RS,omitempty"`
+}
+
+// Group of nodes connected to each other.
+// All components but the largest one are flagged as disconnected.
+type TopoComponent struct {
+	Nodes        []string
+	Disconnected bool
+}
+
+// Trace holds the elements upstream or downstream of a river station
+type Trace struct {
+	Direction     string
+	Reach         string
+	RS            string
+	CrossSections []string `json:"Cross Sections"` // cross sections of the starting reach on the traced side of RS
+	Reaches       []string
+	StorageAreas  []string `json:"Storage Areas"`
+	TwoDAreas     []string `json:"2D Areas"`
+}
+
+// NewTopology builds the topology of a geometry file from its metadata
+func NewTopology(meta GeomFileContents) Topology {
+	t := Topology{
+		GeomFile:   filepath.Base(meta.Path),
+		Nodes:      make(map[string]TopoNode),
+		Edges:      []TopoEdge{},
+		Headwaters: []string{},
+		Outlets:    []string{},
+	}
+
+	for _, structures := range meta.Structures {
+		reach := fmt.Sprintf("%s, %s", structures.River, structures.Reach)
+		t.Nodes[reach] = TopoNode{Type: "Reach", CrossSections: structures.CrossSections}
+	}
+	for name := range meta.StorageAreas {
+		t.Nodes[name] = TopoNode{Type: "Storage Area"}
+	}
+	for name := range meta.TwoDAreas {
+		t.Nodes[name] = TopoNode{Type: "2D Area"}
+	}
+
+	for _, name := range sortedJunctionNames(meta.Junctions) {
+		junction := meta.Junctions[name]
+		for _, up := range junction.UpstreamReaches {
+			for _, dn := range junction.DownstreamReaches {
+				t.Edges = append(t.Edges, TopoEdge{From: up, To: dn, Type: "Junction", Name: name})
+			}
+		}
+	}
+
+	for _, structures := range meta.Structures {
+		reach := fmt.Sprintf("%s, %s", structures.River, structures.Reach)
+		for _, lateral := range structures.LateralData.Laterals {
+			edge := TopoEdge{From: reach, Type: "Lateral Structure", Name: lateral.Name, RS: fmt.Sprint(lateral.Station)}
+			switch {
+			case lateral.ConnectedReach != "":
+				edge.To = lateral.ConnectedReach
+				edge.ToRS = lateral.ConnectedRS
+			case lateral.ConnectedSA != "":
+				edge.To = lateral.ConnectedSA
+			case lateral.Connected2D != "":
+				edge.To = lateral.Connected2D
+			default:
+				continue
+			}
+			t.Edges = append(t.Edges, edge)
+		}
+	}
+
+	connNames := make([]string, 0, len(meta.Connections))
+	for name := range meta.Connections {
+		connNames = append(connNames, name)
+	}
+	sort.Strings(connNames)
+	for _, name := range connNames {
+		conn := meta.Connections[name]
+		if conn.UpSA == "" || conn.DnSA == "" {
+			continue
+		}
+		t.Edges = append(t.Edges, TopoEdge{From: conn.UpSA, To: conn.DnSA, Type: "Connection", Name: name})
+	}
+
+	pumpNames := make([]string, 0, len(meta.PumpStations))
+	for name := range meta.PumpStations {
+		pumpNames = append(pumpNames, name)
+	}
+	sort.Strings(pumpNames)
+	for _, name := range pumpNames {
+		pump := meta.PumpStations[name]
+		edge := TopoEdge{From: pumpNodeName(pump.From), To: pumpNodeName(pump.To), Type: "Pump Station", Name: name, RS: pump.From.RS, ToRS: pump.To.RS}
+		if edge.From == "" || edge.To == "" {
+			continue
+		}
+		t.Edges = append(t.Edges, edge)
+	}
+
+	// edges can reference elements that are not defined in the geometry file
+	for _, edge := range t.Edges {
+		for _, name := range []string{edge.From, edge.To} {
+			if _, ok := t.Nodes[name]; !ok {
+				t.Nodes[name] = TopoNode{Type: "Undefined"}
+			}
+		}
+	}
+
+	t.Headwaters, t.Outlets = t.reachEnds()
+	t.Components = t.components()
+	return t
+}
+
+// Topology node of the source or destination of a pump station
+func pumpNodeName(location pumpLocation) string {
+	switch {
+	case location.Reach != "":
+		return location.Reach
+	case location.TwoDArea != "":
+		return location.TwoDArea
+	}
+	return location.StorageArea
+}
+
+func sortedJunctionNames(junctions map[string]Junction) []string {
+	names := make([]string, 0, len(junctions))
+	for name := range junctions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (t Topology) sortedNodes() []string {
+	names := make([]string, 0, len(t.Nodes))
+	for name := range t.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Headwater reaches have no reach flowing into them, outlet reaches do not flow into any reach
+func (t Topology) reachEnds() ([]string, []string) {
+	headwaters, outlets := []string{}, []string{}
+	hasInflow := make(map[string]bool)
+	hasOutflow := make(map[string]bool)
+	for _, edge := range t.Edges {
+		if edge.Type == "Junction" {
+			hasOutflow[edge.From] = true
+			hasInflow[edge.To] = true
+		}
+	}
+
+	for _, name := range t.sortedNodes() {
+		if t.Nodes[name].Type != "Reach" {
+			continue
+		}
+		if !hasInflow[name] {
+			headwaters = append(headwaters, name)
+		}
+		if !hasOutflow[name] {
+			outlets = append(outlets, name)
+		}
+	}
+	return headwaters, outlets
+}
+
+// Group nodes that are connected to each other regardless of flow direction
+func (t Topology) components() []TopoComponent {
+	parent := make(map[string]string, len(t.Nodes))
+	var find func(string) string
+	find = func(n string) string {
+		if parent[n] != n {
+			parent[n] = find(parent[n])
+		}
+		return parent[n]
+	}
+	for name := range t.Nodes {
+		parent[name] = name
+	}
+	for _, edge := range t.Edges {
+		parent[find(edge.From)] = find(edge.To)
+	}
+
+	groups := make(map[string][]string)
+	for _, name := range t.sortedNodes() {
+		root := find(name)
+		groups[root] = append(groups[root], name)
+	}
+
+	components := make([]TopoComponent, 0, len(groups))
+	for _, nodes := range groups {
+		components = append(components, TopoComponent{Nodes: nodes, Disconnected: true})
+	}
+	sort.Slice(components, func(i, j int) bool {
+		if len(components[i].Nodes) != len(components[j].Nodes) {
+			return len(components[i].Nodes) > len(components[j].Nodes)
+		}
+		return components[i].Nodes[0] < components[j].Nodes[0]
+	})
+	if len(components) > 0 {
+		components[0].Disconnected = false
+	}
+	return components
+}
+
+// Trace returns all elements upstream or downstream of a river station of a reach.
+// Cross sections with a river station greater than the given one are upstream.
+func (t Topology) Trace(reach string, rs string, upstream bool) (Trace, error) {
+	trace := Trace{
+		Direction:     "downstream",
+		Reach:         reach,
+		RS:            rs,
+		CrossSections: []string{},
+		Reaches:       []string{},
+		StorageAreas:  []string{},
+		TwoDAreas:     []string{},
+	}
+	if upstream {
+		trace.Direction = "upstream"
+	}
+
+	node, ok := t.Nodes[reach]
+	if !ok || node.Type != "Reach" {
+		return trace, errors.Errorf("reach '%s' does not exist in %s", reach, t.GeomFile)
+	}
+
+	station, err := parseRS(rs)
+	if err != nil {
+		return trace, errors.Wrap(err, 0)
+	}
+
+	for _, xs := range node.CrossSections {
+		if (upstream && xs.Station >= station) || (!upstream && xs.Station <= station) {
+			trace.CrossSections = append(trace.CrossSections, xs.Name)
+		}
+	}
+
+	// only the part of the starting reach on the traced side of RS is crossed
+	queue := []string{}
+	for _, edge := range t.Edges {
+		switch {
+		case upstream && edge.To == reach && edge.Type == "Junction":
+			queue = append(queue, edge.From)
+		case upstream && edge.To == reach && edge.ToRS != "" && rsOnSide(edge.ToRS, station, upstream):
+			queue = append(queue, edge.From)
+		case !upstream && edge.From == reach && edge.Type == "Junction":
+			queue = append(queue, edge.To)
+		case !upstream && edge.From == reach && edge.RS != "" && rsOnSide(edge.RS, station, upstream):
+			queue = append(queue, edge.To)
+		}
+	}
+
+	visited := map[string]bool{reach: true}
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if visited[name] {
+			continue
+		}
+		visited[name] = true
+
+		switch t.Nodes[name].Type {
+		case "Reach":
+			trace.Reaches = append(trace.Reaches, name)
+		case "Storage Area":
+			trace.StorageAreas = append(trace.StorageAreas, name)
+		case "2D Area":
+			trace.TwoDAreas = append(trace.TwoDAreas, name)
+		}
+
+		for _, edge := range t.Edges {
+			if upstream && edge.To == name {
+				queue = append(queue, edge.From)
+			} else if !upstream && edge.From == name {
+				queue = append(queue, edge.To)
+			}
+		}
+	}
+
+	sort.Strings(trace.Reaches)
+	sort.Strings(trace.StorageAreas)
+	sort.Strings(trace.TwoDAreas)
+	return trace, nil
+}
+
+func parseRS(rs string) (float64, error) {
+	numericRS, err := toNumeric(rs)
+	if err != nil {
+		return 0, errors.Wrap(err, 0)
+	}
+	if numericRS == "" {
+		return 0, errors.Errorf("'%s' is not a valid river station", rs)
+	}
+	return parseFloat(numericRS, 64)
+}
+
+// Check if a river station is on the traced side of a starting station
+func rsOnSide(rs string, station float64, upstream bool) bool {
+	s, err := parseRS(rs)
+	if err != nil {
+		return false
+	}
+	if upstream {
+		return s >= station
+	}
+	return s <= station
+}
+
+// Topology builds the topology of a geometry file of the model.
+// The geometry file is identified by its extension e.g. g01
+func (rm *RasModel) Topology(geomExt string) (Topology, error) {
+	geomExt = "." + strings.TrimPrefix(geomExt, ".")
+	for _, g := range rm.Metadata.GeomFiles {
+		if g.FileExt == geomExt {
+			return NewTopology(g), nil
+		}
+	}
+	return Topology{}, errors.Errorf("geometry file %s does not exist in the model", geomExt)
+}
@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopologyTrace(t *testing.T) {
+	xs := func(names ...string) []crossSections {
+		sections := []crossSections{}
+		for _, name := range names {
+			station, _ := parseRS(name)
+			sections = append(sections, crossSections{Name: name, Station: station})
+		}
+		return sections
+	}
+
+	topo := Topology{
+		GeomFile: "model.g01",
+		Nodes: map[string]TopoNode{
+			"Creek, Upper":  {Type: "Reach", CrossSections: xs("300", "200")},
+			"Trib, Trib":    {Type: "Reach", CrossSections: xs("20")},
+			"Creek, Lower":  {Type: "Reach", CrossSections: xs("100", "50", "45.5*", "10")},
+			"Side, Channel": {Type: "Reach", CrossSections: xs("5")},
+			"Pond":          {Type: "Storage Area"},
+			"Marsh":         {Type: "Storage Area"},
+			"Floodplain":    {Type: "2D Area"},
+		},
+		Edges: []TopoEdge{
+			{From: "Creek, Upper", To: "Creek, Lower", Type: "Junction", Name: "Confluence"},
+			{From: "Trib, Trib", To: "Creek, Lower", Type: "Junction", Name: "Confluence"},
+			{From: "Creek, Lower", To: "Pond", Type: "Lateral Structure", Name: "Levee", RS: "80"},
+			{From: "Creek, Lower", To: "Floodplain", Type: "Lateral Structure", Name: "Spill", RS: "20"},
+			{From: "Pond", To: "Marsh", Type: "Connection", Name: "Culvert"},
+			{From: "Side, Channel", To: "Creek, Lower", Type: "Lateral Structure", Name: "Weir", RS: "5", ToRS: "30"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		reach    string
+		rs       string
+		upstream bool
+		expected Trace
+	}{
+		{
+			"downstream below a lateral structure", "Creek, Lower", "50", false,
+			Trace{
+				Direction: "downstream", Reach: "Creek, Lower", RS: "50", CrossSections: []string{"50", "45.5*", "10"},
+				Reaches: []string{}, StorageAreas: []string{}, TwoDAreas: []string{"Floodplain"},
+			},
+		},
+		{
+			"downstream above a lateral structure", "Creek, Lower", "90", false,
+			Trace{
+				Direction: "downstream", Reach: "Creek, Lower", RS: "90", CrossSections: []string{"50", "45.5*", "10"},
+				Reaches: []string{}, StorageAreas: []string{"Marsh", "Pond"}, TwoDAreas: []string{"Floodplain"},
+			},
+		},
+		{
+			"downstream through a junction", "Creek, Upper", "250", false,
+			Trace{
+				Direction: "downstream", Reach: "Creek, Upper", RS: "250", CrossSections: []string{"200"},
+				Reaches: []string{"Creek, Lower"}, StorageAreas: []string{"Marsh", "Pond"}, TwoDAreas: []string{"Floodplain"},
+			},
+		},
+		{
+			"upstream above a connected reach", "Creek, Lower", "50", true,
+			Trace{
+				Direction: "upstream", Reach: "Creek, Lower", RS: "50", CrossSections: []string{"100", "50"},
+				Reaches: []string{"Creek, Upper", "Trib, Trib"}, StorageAreas: []string{}, TwoDAreas: []string{},
+			},
+		},
+		{
+			"upstream below a connected reach", "Creek, Lower", "20*", true,
+			Trace{
+				Direction: "upstream", Reach: "Creek, Lower", RS: "20*", CrossSections: []string{"100", "50", "45.5*"},
+				Reaches: []string{"Creek, Upper", "Side, Channel", "Trib, Trib"}, StorageAreas: []string{}, TwoDAreas: []string{},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			trace, err := topo.Trace(tc.reach, tc.rs, tc.upstream)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(trace, tc.expected) {
+				t.Errorf("got %+v, want %+v", trace, tc.expected)
+			}
+		})
+	}
+
+	errorTests := []struct {
+		name  string
+		reach string
+		rs    string
+	}{
+		{"unknown reach", "Creek, Missing", "50"},
+		{"storage area", "Pond", "50"},
+		{"invalid river station", "Creek, Lower", "upstream"},
+	}
+	for _, tc := range errorTests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := topo.Trace(tc.reach, tc.rs, true); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dewberry/gdal"
+	"github.com/go-errors/errors" // warning: replaces standard errors
+)
+
+// Coordinate transformation taking and returning coordinates in the traditional GIS order, easting or longitude first.
+// Since GDAL 3 coordinates follow the axis order of the CRS definition, which is northing or latitude first
+// for CRSs such as EPSG:4326, so the axes of those CRSs are swapped around the transformation.
+type gisTransform struct {
+	ct           gdal.CoordinateTransform
+	native       bool // coordinates are kept in the model projection
+	swapSourceXY bool
+	swapDestXY   bool
+}
+
+// NativeCRS is the destination CRS keeping coordinates in the projection of the model
+const NativeCRS = "native"
+
+// Create a spatial reference from an EPSG code, WKT or PROJ string
+func spatialReference(crs string) (gdal.SpatialReference, error) {
+	spRef := gdal.CreateSpatialReference("")
+	if code, err := strconv.Atoi(strings.TrimSpace(crs)); err == nil {
+		if err := spRef.FromEPSG(code); err != nil {
+			return spRef, errors.Wrap(err, 0)
+		}
+		return spRef, nil
+	}
+	if err := spRef.SetFromUserInput(crs); err != nil {
+		return spRef, errors.Errorf("invalid CRS '%s': %v", crs, err)
+	}
+	return spRef, nil
+}
+
+// ValidCRS checks that a destination CRS can be understood
+func ValidCRS(crs string) bool {
+	if crs == NativeCRS {
+		return true
+	}
+	_, err := spatialReference(crs)
+	return err == nil
+}
+
+// CRSWKT returns the WKT of a CRS given as an EPSG code, WKT or PROJ string
+func CRSWKT(crs string) (string, error) {
+	spRef, err := spatialReference(crs)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	wkt, err := spRef.ToWKT()
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	return wkt, nil
+}
+
+// GeoreferenceWKT returns the WKT of the destination CRS, the model projection for the native CRS
+func GeoreferenceWKT(sourceCRS string, destinationCRS string) (string, error) {
+	if destinationCRS == NativeCRS {
+		wkt, err := gdal.CreateSpatialReference(sourceCRS).ToWKT()
+		if err != nil {
+			return "", errors.Wrap(err, 0)
+		}
+		return wkt, nil
+	}
+
+	wkt, err := CRSWKT(destinationCRS)
+	if err != nil {
+		return "", errors.Wrap(err, 0)
+	}
+	return wkt, nil
+}
+
+func getTransform(sourceCRS string, destinationCRS string) (gisTransform, error) {
+	transform := gisTransform{}
+	if destinationCRS == NativeCRS {
+		transform.native = true
+		return transform, nil
+	}
+	sourceSpRef := gdal.CreateSpatialReference(sourceCRS)
+
+	destinationSpRef, err := spatialReference(destinationCRS)
+	if err != nil {
+		return transform, errors.Wrap(err, 0)
+	}
+	transform.ct = gdal.CreateCoordinateTransform(sourceSpRef, destinationSpRef)
+
+	if gdal.VERSION_MAJOR >= 3 {
+		transform.swapSourceXY = northingFirst(sourceSpRef)
+		transform.swapDestXY = northingFirst(destinationSpRef)
+	}
+	return transform, nil
+}
+
+// Check if the first axis of a spatial reference points north or south
+func northingFirst(spRef gdal.SpatialReference) bool {
+	if spRef.EPSGTreatsAsLatLong() {
+		return true
+	}
+
+	root := "GEOGCS"
+	if spRef.IsProjected() {
+		root = "PROJCS"
+	}
+	direction, ok := spRef.AttrValue(root+"|AXIS", 1)
+	return ok && (strings.EqualFold(direction, "NORTH") || strings.EqualFold(direction, "SOUTH"))
+}
+
+// Transform a geometry in place
+func (t gisTransform) apply(geom gdal.Geometry) {
+	if t.native {
+		return
+	}
+	if t.swapSourceXY {
+		swapXY(geom)
+	}
+	geom.Transform(t.ct)
+	if t.swapDestXY {
+		swapXY(geom)
+	}
+}
+
+// Swap the x and y coordinates of a geometry and of its sub geometries in place
+func swapXY(geom gdal.Geometry) {
+	for i := 0; i < geom.GeometryCount(); i++ {
+		swapXY(geom.Geometry(i))
+	}
+
+	is3D := geom.CoordinateDimension() == 3
+	for i := 0; i < geom.PointCount(); i++ {
+		x, y, z := geom.Point(i)
+		if is3D {
+			geom.SetPoint(i, y, x, z)
+		} else {
+			geom.SetPoint2D(i, y, x)
+		}
+	}
+}
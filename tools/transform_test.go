@@ -0,0 +1,140 @@
+package tools
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dewberry/gdal"
+)
+
+// Projected CRS without authority, with easting first
+const utm18NWKT = `PROJCS["UTM 18N",GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]],` +
+	`PRIMEM["Greenwich",0],UNIT["degree",0.0174532925199433]],PROJECTION["Transverse_Mercator"],` +
+	`PARAMETER["latitude_of_origin",0],PARAMETER["central_meridian",-75],PARAMETER["scale_factor",0.9996],` +
+	`PARAMETER["false_easting",500000],PARAMETER["false_northing",0],UNIT["metre",1],AXIS["Easting",EAST],AXIS["Northing",NORTH]]`
+
+func TestValidCRS(t *testing.T) {
+	tests := []struct {
+		crs      string
+		expected bool
+	}{
+		{NativeCRS, true},
+		{"4326", true},
+		{" 26918 ", true},
+		{"EPSG:26918", true},
+		{"+proj=utm +zone=18 +datum=WGS84 +units=m +no_defs", true},
+		{utm18NWKT, true},
+		{"not a crs", false},
+	}
+	for _, tc := range tests {
+		if got := ValidCRS(tc.crs); got != tc.expected {
+			t.Errorf("ValidCRS(%q): got %v, want %v", tc.crs, got, tc.expected)
+		}
+	}
+}
+
+func TestGetTransform(t *testing.T) {
+	tests := []struct {
+		name           string
+		destinationCRS string
+		native         bool
+		swapDestXY     bool
+	}{
+		{"native", NativeCRS, true, false},
+		{"geographic", "4326", false, true},
+		{"projected", "26918", false, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			transform, err := getTransform(utm18NWKT, tc.destinationCRS)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if transform.native != tc.native || transform.swapSourceXY || transform.swapDestXY != tc.swapDestXY {
+				t.Errorf("got %+v, want native %v and swapDestXY %v", transform, tc.native, tc.swapDestXY)
+			}
+		})
+	}
+
+	if _, err := getTransform(utm18NWKT, "not a crs"); err == nil {
+		t.Error("expected an error for an invalid destination CRS")
+	}
+}
+
+func TestNorthingFirst(t *testing.T) {
+	fromEPSG := func(code int) gdal.SpatialReference {
+		spRef := gdal.CreateSpatialReference("")
+		if err := spRef.FromEPSG(code); err != nil {
+			t.Fatal(err)
+		}
+		return spRef
+	}
+
+	tests := []struct {
+		name     string
+		spRef    gdal.SpatialReference
+		expected bool
+	}{
+		{"EPSG:4326", fromEPSG(4326), true},
+		{"projected EPSG:26918", fromEPSG(26918), false},
+		{
+			"geographic WKT with latitude first",
+			gdal.CreateSpatialReference(`GEOGCS["WGS 84",DATUM["WGS_1984",SPHEROID["WGS 84",6378137,298.257223563]],` +
+				`PRIMEM["Greenwich",0],UNIT["degree",0.0174532925199433],AXIS["Latitude",NORTH],AXIS["Longitude",EAST]]`),
+			true,
+		},
+		{
+			"projected WKT with easting first",
+			gdal.CreateSpatialReference(utm18NWKT),
+			false,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := northingFirst(tc.spRef); got != tc.expected {
+				t.Errorf("got %v, want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestSwapXY(t *testing.T) {
+	points := func(geom gdal.Geometry) [][3]float64 {
+		pts := [][3]float64{}
+		for i := 0; i < geom.PointCount(); i++ {
+			x, y, z := geom.Point(i)
+			pts = append(pts, [3]float64{x, y, z})
+		}
+		return pts
+	}
+
+	lineString := gdal.Create(gdal.GT_LineString)
+	lineString.AddPoint2D(1, 2)
+	lineString.AddPoint2D(3, 4)
+	swapXY(lineString)
+	if got, expected := points(lineString), [][3]float64{{2, 1, 0}, {4, 3, 0}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("line string: got %v, want %v", got, expected)
+	}
+
+	xyzLineString := gdal.Create(gdal.GT_LineString25D)
+	xyzLineString.AddPoint(1, 2, 10)
+	xyzLineString.AddPoint(3, 4, 20)
+	multiLineString := xyzLineString.ForceToMultiLineString()
+	swapXY(multiLineString)
+	got := points(multiLineString.Geometry(0))
+	if expected := [][3]float64{{2, 1, 10}, {4, 3, 20}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("3D multi line string: got %v, want %v", got, expected)
+	}
+
+	ring := gdal.Create(gdal.GT_LinearRing)
+	for _, pair := range [][2]float64{{0, 0}, {0, 10}, {5, 10}, {0, 0}} {
+		ring.AddPoint2D(pair[0], pair[1])
+	}
+	polygon := gdal.Create(gdal.GT_Polygon)
+	polygon.AddGeometryDirectly(ring)
+	swapXY(polygon)
+	got = points(polygon.Geometry(0))
+	if expected := [][3]float64{{0, 0, 0}, {10, 0, 0}, {10, 5, 0}, {0, 0, 0}}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("polygon: got %v, want %v", got, expected)
+	}
+}